@@ -0,0 +1,104 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshPort is the port the SSH daemon of a server is polled on after a reset.
+var sshPort = "22"
+
+// sshPollInterval is the wait between two connection attempts to a server which is
+// booting.
+var sshPollInterval = 10 * time.Second
+
+// sshAttemptTimeout limits a single connection attempt including the key exchange.
+const sshAttemptTimeout = 10 * time.Second
+
+// errHostKeyReceived aborts the handshake once the host key is known, as nothing is
+// authenticated.
+var errHostKeyReceived = errors.New("host key received")
+
+// waitForSSH polls the SSH daemon on ip until it presents one of hostKeys, the keys
+// Robot reports for the armed profile. Until then the previous system may still be
+// answering, so other keys are retried as well.
+func waitForSSH(ctx context.Context, ip string, hostKeys []robot.BootKey) error {
+	if len(hostKeys) == 0 {
+		return errors.New("Robot reports no host keys to verify the server against")
+	}
+	fingerprints := make(map[string]bool, len(hostKeys))
+	for _, key := range hostKeys {
+		fingerprints[key.Fingerprint] = true
+	}
+
+	address := net.JoinHostPort(ip, sshPort)
+	ticker := time.NewTicker(sshPollInterval)
+	defer ticker.Stop()
+
+	var last error
+	for {
+		fingerprint, err := sshHostKey(ctx, address)
+		switch {
+		case err == nil && fingerprints[fingerprint]:
+			return nil
+		case err == nil:
+			err = fmt.Errorf("host key %s is not one of the host keys reported by Robot", fingerprint)
+		}
+		// an attempt cut short by the timeout tells nothing about the server
+		if last == nil || !expired(ctx) {
+			last = err
+		}
+
+		tflog.Debug(ctx, "waiting for SSH", map[string]interface{}{"address": address, "error": err.Error()})
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for SSH on %s with a host key reported by Robot, last attempt: %w", address, last)
+		case <-ticker.C:
+		}
+	}
+}
+
+// expired reports whether the deadline of ctx passed. Dialing fails on the deadline
+// itself, which may be a moment before ctx reports it.
+func expired(ctx context.Context) bool {
+	deadline, ok := ctx.Deadline()
+	return ctx.Err() != nil || ok && !time.Now().Before(deadline)
+}
+
+// sshHostKey connects to the SSH daemon on address and returns the MD5 fingerprint
+// of the host key it presents.
+func sshHostKey(ctx context.Context, address string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, sshAttemptTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	var fingerprint string
+	_, _, _, err = ssh.NewClientConn(conn, address, &ssh.ClientConfig{
+		User: "root",
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			fingerprint = ssh.FingerprintLegacyMD5(key)
+			return errHostKeyReceived
+		},
+	})
+	if fingerprint == "" {
+		return "", err
+	}
+	return fingerprint, nil
+}
@@ -6,12 +6,19 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
 func dataBoot() *schema.Resource {
 	return &schema.Resource{
+		Description: "Boot configuration of the server selected by `server_number`.",
 		ReadContext: dataSourceBootRead,
 		Schema: map[string]*schema.Schema{
+			"server_number": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Server number",
+			},
 			// read-only / computed
 			"active_profile": {
 				Type:        schema.TypeString, // Enum should be better (linux/rescue/...)
@@ -33,6 +40,11 @@ func dataBoot() *schema.Resource {
 				Computed:    true,
 				Description: "Language",
 			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hostname of a plesk or cpanel installation",
+			},
 			"operating_system": {
 				Type:        schema.TypeString, // Enum should be better (ubuntu_20.04/...)
 				Computed:    true,
@@ -44,22 +56,63 @@ func dataBoot() *schema.Resource {
 				Description: "Current Rescue System root password / Linux installation password or null",
 				Sensitive:   true,
 			},
+			"architecture": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Architecture, 64 or 32 bit",
+			},
+			"keyboard": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Keyboard layout of the rescue system",
+			},
+			"authorized_keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Fingerprints of the authorized SSH keys",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"authorized_key_details": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Fingerprint, type and size of the authorized SSH keys",
+				Elem:        bootKeySchema(),
+			},
+			"host_keys": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Fingerprint, type and size of the host keys of the rescue system or installation",
+				Elem:        bootKeySchema(),
+			},
 		},
-		/*
-			AuthorizedKeys []string		    authorized_key (Array)	Authorized public SSH keys
-			HostKeys []string				host_key (Array)	Host keys
-		*/
 	}
 }
-func dataSourceBootRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
 
-	serverNumber, err := strconv.Atoi(d.Id())
-	if err != nil {
-		return diag.FromErr(err)
+func bootKeySchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"fingerprint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
 	}
+}
 
-	boot, err := c.getBoot(ctx, serverNumber)
+func dataSourceBootRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*robot.Client)
+
+	serverNumber := d.Get("server_number").(int)
+
+	boot, err := c.GetBoot(ctx, serverNumber)
 	if err != nil {
 		return diag.Errorf("Unable to find Boot Profile for server ID %d:\n\t %q", serverNumber, err)
 	}
@@ -68,8 +121,18 @@ func dataSourceBootRead(ctx context.Context, d *schema.ResourceData, meta interf
 	d.Set("ipv4_address", boot.ServerIPv4)
 	d.Set("ipv6_network", boot.ServerIPv6)
 	d.Set("language", boot.Language)
+	d.Set("hostname", boot.Hostname)
 	d.Set("operating_system", boot.OperatingSystem)
 	d.Set("password", boot.Password)
+	d.Set("architecture", boot.Architecture)
+	d.Set("keyboard", boot.Keyboard)
+	fingerprints := make([]string, 0, len(boot.AuthorizedKeys))
+	for _, key := range boot.AuthorizedKeys {
+		fingerprints = append(fingerprints, key.Fingerprint)
+	}
+	d.Set("authorized_keys", fingerprints)
+	d.Set("authorized_key_details", flattenBootKeyMaps(boot.AuthorizedKeys))
+	d.Set("host_keys", flattenBootKeyMaps(boot.HostKeys))
 	d.SetId(strconv.Itoa(serverNumber))
 
 	// Warning or errors can be collected in a slice type
@@ -77,3 +140,15 @@ func dataSourceBootRead(ctx context.Context, d *schema.ResourceData, meta interf
 
 	return diags
 }
+
+func flattenBootKeyMaps(keys []robot.BootKey) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, map[string]interface{}{
+			"fingerprint": key.Fingerprint,
+			"type":        key.Type,
+			"size":        key.Size,
+		})
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+var bootOptionsType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"active":            types.BoolType,
+	"operating_systems": types.ListType{ElemType: types.StringType},
+	"languages":         types.ListType{ElemType: types.StringType},
+	"architectures":     types.ListType{ElemType: types.Int64Type},
+	"keyboards":         types.ListType{ElemType: types.StringType},
+}}
+
+type bootOptionsDataSource struct {
+	client *robot.Client
+}
+
+type bootOptionsDataSourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	ServerNumber types.Int64  `tfsdk:"server_number"`
+	Profiles     types.Map    `tfsdk:"profiles"`
+}
+
+func newBootOptionsDataSource() datasource.DataSource {
+	return &bootOptionsDataSource{}
+}
+
+func (d *bootOptionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_boot_options"
+}
+
+func (d *bootOptionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"server_number": schema.Int64Attribute{
+				Required:    true,
+				Description: "Server number",
+			},
+			"profiles": schema.MapAttribute{
+				Computed:    true,
+				Description: "Options keyed by boot profile: operating_systems, languages, architectures and keyboards, empty for the armed profile",
+				ElementType: bootOptionsType,
+			},
+		},
+	}
+}
+
+func (d *bootOptionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = dataSourceClient(req, resp)
+}
+
+func (d *bootOptionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config bootOptionsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serverNumber := int(config.ServerNumber.ValueInt64())
+	options, err := d.client.GetBootOptions(ctx, serverNumber)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to read boot options of server %d", serverNumber), err.Error())
+		return
+	}
+
+	profiles := make(map[string]attr.Value, len(options))
+	for profile, profileOptions := range options {
+		value, diags := flattenBootOptions(ctx, profileOptions)
+		resp.Diagnostics.Append(diags...)
+		profiles[profile] = value
+	}
+	profileMap, diags := types.MapValue(bootOptionsType, profiles)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state := bootOptionsDataSourceModel{
+		ID:           types.StringValue(strconv.Itoa(serverNumber)),
+		ServerNumber: config.ServerNumber,
+		Profiles:     profileMap,
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func flattenBootOptions(ctx context.Context, options robot.BootOptions) (attr.Value, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	architectures := make([]int64, 0, len(options.Architectures))
+	for _, architecture := range options.Architectures {
+		architectures = append(architectures, int64(architecture))
+	}
+
+	operatingSystems, d := types.ListValueFrom(ctx, types.StringType, options.OperatingSystems)
+	diags.Append(d...)
+	languages, d := types.ListValueFrom(ctx, types.StringType, options.Languages)
+	diags.Append(d...)
+	architectureList, d := types.ListValueFrom(ctx, types.Int64Type, architectures)
+	diags.Append(d...)
+	keyboards, d := types.ListValueFrom(ctx, types.StringType, options.Keyboards)
+	diags.Append(d...)
+
+	value, d := types.ObjectValue(bootOptionsType.AttrTypes, map[string]attr.Value{
+		"active":            types.BoolValue(options.Active),
+		"operating_systems": operatingSystems,
+		"languages":         languages,
+		"architectures":     architectureList,
+		"keyboards":         keyboards,
+	})
+	diags.Append(d...)
+	return value, diags
+}
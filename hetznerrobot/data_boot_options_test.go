@@ -0,0 +1,53 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestDataBootOptions(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	client := testClient(fake)
+	ctx := context.Background()
+
+	if _, err := client.SetBootProfile(ctx, 321, "linux", robot.BootProfileOptions{OperatingSystem: "Debian 12 base", Language: "en"}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := newTestDataSource(t, newBootOptionsDataSource(), fake)
+	state, diags := d.read(bootOptionsDataSourceModel{ServerNumber: types.Int64Value(321), Profiles: types.MapNull(bootOptionsType)})
+	if diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	var model bootOptionsDataSourceModel
+	d.get(state, &model)
+	var profiles map[string]struct {
+		Active           bool     `tfsdk:"active"`
+		OperatingSystems []string `tfsdk:"operating_systems"`
+		Languages        []string `tfsdk:"languages"`
+		Architectures    []int64  `tfsdk:"architectures"`
+		Keyboards        []string `tfsdk:"keyboards"`
+	}
+	if diags := model.Profiles.ElementsAs(ctx, &profiles, false); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	if model.ID.ValueString() != "321" || len(profiles) != len(robot.BootProfiles) {
+		t.Fatalf("unexpected profiles of server %s: %+v", model.ID, profiles)
+	}
+	if rescue := profiles["rescue"]; rescue.Active || len(rescue.OperatingSystems) == 0 || len(rescue.Keyboards) == 0 || len(rescue.Architectures) == 0 {
+		t.Errorf("expected the options of the rescue system, got %+v", rescue)
+	}
+	if vnc := profiles["vnc"]; len(vnc.OperatingSystems) == 0 || len(vnc.Languages) == 0 || len(vnc.Keyboards) != 0 {
+		t.Errorf("expected the distributions and languages of vnc, got %+v", vnc)
+	}
+	if linux := profiles["linux"]; !linux.Active || len(linux.OperatingSystems) != 0 {
+		t.Errorf("expected no options for the armed linux profile, got %+v", linux)
+	}
+}
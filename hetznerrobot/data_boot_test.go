@@ -0,0 +1,58 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestAccDataBoot_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t, fake),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "hetzner-robot_boot" "test" {
+  server_number = 321
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot.test", "ipv4_address", "192.0.2.10"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_boot.test", "active_profile", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestDataBoot(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	client := testClient(fake)
+	ctx := context.Background()
+
+	if _, err := client.SetBootProfile(ctx, 321, "linux", robot.BootProfileOptions{OperatingSystem: "Debian 12 base", Language: "en"}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, dataBoot().Schema, map[string]interface{}{
+		"server_number": 321,
+	})
+	if diags := dataSourceBootRead(ctx, d, client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if d.Id() != "321" || d.Get("active_profile") != "linux" || d.Get("operating_system") != "Debian 12 base" || d.Get("language") != "en" {
+		t.Errorf("unexpected boot profile: %v / %v / %v", d.Get("active_profile"), d.Get("operating_system"), d.Get("language"))
+	}
+	if d.Get("architecture") != 64 || d.Get("host_keys.#") != 2 || d.Get("host_keys.0.type") != "ED25519" {
+		t.Errorf("unexpected architecture %v or host keys %v", d.Get("architecture"), d.Get("host_keys"))
+	}
+}
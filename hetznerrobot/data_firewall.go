@@ -0,0 +1,129 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+type firewallDataSource struct {
+	client *robot.Client
+}
+
+type firewallDataSourceModel struct {
+	ID           types.String        `tfsdk:"id"`
+	ServerIP     types.String        `tfsdk:"server_ip"`
+	ServerNumber types.Int64         `tfsdk:"server_number"`
+	Status       types.String        `tfsdk:"status"`
+	WhitelistHOS types.Bool          `tfsdk:"whitelist_hos"`
+	FilterIPv6   types.Bool          `tfsdk:"filter_ipv6"`
+	Port         types.String        `tfsdk:"port"`
+	Rule         []firewallRuleModel `tfsdk:"rule"`
+	OutputRule   []firewallRuleModel `tfsdk:"output_rule"`
+}
+
+func newFirewallDataSource() datasource.DataSource {
+	return &firewallDataSource{}
+}
+
+func (d *firewallDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall"
+}
+
+func (d *firewallDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"server_ip": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Main IP of the server, exactly one of server_ip and server_number is required",
+			},
+			"server_number": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Number of the server, exactly one of server_ip and server_number is required",
+			},
+			"status": schema.StringAttribute{
+				Computed:    true,
+				Description: "active, disabled or in process",
+			},
+			"whitelist_hos": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether Hetzner services are allowed",
+			},
+			"filter_ipv6": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether IPv6 traffic is filtered",
+			},
+			"port": schema.StringAttribute{
+				Computed:    true,
+				Description: "Switch port the firewall is applied to",
+			},
+			"rule": schema.ListAttribute{
+				Computed:    true,
+				Description: "Incoming traffic rules",
+				ElementType: firewallRuleType,
+			},
+			"output_rule": schema.ListAttribute{
+				Computed:    true,
+				Description: "Outgoing traffic rules",
+				ElementType: firewallRuleType,
+			},
+		},
+	}
+}
+
+func (d *firewallDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = dataSourceClient(req, resp)
+}
+
+func (d *firewallDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config firewallDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateFirewallServer(config.ServerIP, config.ServerNumber)...)
+}
+
+func (d *firewallDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config firewallDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	server := config.ServerIP.ValueString()
+	if config.ServerIP.IsNull() {
+		server = strconv.FormatInt(config.ServerNumber.ValueInt64(), 10)
+	}
+
+	firewall, err := d.client.GetFirewall(ctx, server)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to read firewall of server %s", server), err.Error())
+		return
+	}
+
+	state := firewallDataSourceModel{
+		ID:           types.StringValue(firewall.IP),
+		ServerIP:     types.StringValue(firewall.IP),
+		ServerNumber: types.Int64Value(int64(firewall.ServerNumber)),
+		Status:       types.StringValue(firewall.Status),
+		WhitelistHOS: types.BoolValue(firewall.WhitelistHetznerServices),
+		FilterIPv6:   types.BoolValue(firewall.FilterIPv6),
+		Port:         types.StringValue(firewall.Port),
+		Rule:         flattenFirewallRules(firewall.Rules.Input),
+		OutputRule:   flattenFirewallRules(firewall.Rules.Output),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
@@ -0,0 +1,195 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// firewallPresets are vetted incoming rules by preset name, in the order they are
+// added to a rule set.
+var firewallPresets = []struct {
+	name  string
+	rules []robot.FirewallRule
+}{
+	{"established", []robot.FirewallRule{
+		{Name: "established", Protocol: "tcp", DstPort: "32768-65535", TCPFlags: "ack", Action: "accept"},
+	}},
+	{"icmp", []robot.FirewallRule{
+		{Name: "icmp", Protocol: "icmp", Action: "accept"},
+	}},
+	{"hetzner_dns", hetznerDNSRules("hetzner_dns", hetznerResolvers)},
+	{"hetzner_dns_legacy", hetznerDNSRules("hetzner_dns_legacy", hetznerLegacyResolvers)},
+	{"ntp", []robot.FirewallRule{
+		{Name: "ntp", SrcPort: "123", Protocol: "udp", Action: "accept"},
+	}},
+	{"ssh", []robot.FirewallRule{
+		{Name: "ssh", DstPort: "22", Protocol: "tcp", Action: "accept"},
+	}},
+	{"http", []robot.FirewallRule{
+		{Name: "http", DstPort: "80", Protocol: "tcp", Action: "accept"},
+	}},
+	{"https", []robot.FirewallRule{
+		{Name: "https", DstPort: "443", Protocol: "tcp", Action: "accept"},
+	}},
+}
+
+// hetznerDNSRules accept DNS answers from every network of resolvers. Rules of more
+// than one network are numbered, so named rules can tell them apart.
+func hetznerDNSRules(name string, resolvers []netip.Prefix) []robot.FirewallRule {
+	rules := make([]robot.FirewallRule, 0, len(resolvers))
+	for idx, network := range resolvers {
+		ruleName := name
+		if len(resolvers) > 1 {
+			ruleName = fmt.Sprintf("%s_%d", name, idx+1)
+		}
+		rules = append(rules, robot.FirewallRule{
+			Name:      ruleName,
+			IPVersion: "ipv4",
+			SrcIP:     network.String(),
+			SrcPort:   "53",
+			DstPort:   "32768-65535",
+			Protocol:  "udp",
+			Action:    "accept",
+		})
+	}
+	return rules
+}
+
+type firewallRulesetDataSource struct{}
+
+type firewallRulesetDataSourceModel struct {
+	ID      types.String        `tfsdk:"id"`
+	Presets []types.String      `tfsdk:"presets"`
+	Rule    []firewallRuleModel `tfsdk:"rule"`
+	Rules   []firewallRuleModel `tfsdk:"rules"`
+}
+
+func newFirewallRulesetDataSource() datasource.DataSource {
+	return &firewallRulesetDataSource{}
+}
+
+func (d *firewallRulesetDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_ruleset"
+}
+
+func (d *firewallRulesetDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	presets := make([]string, 0, len(firewallPresets))
+	for _, preset := range firewallPresets {
+		presets = append(presets, preset.name)
+	}
+
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"presets": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: fmt.Sprintf("Presets to include, any of %v", presets),
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(presets...)),
+				},
+			},
+			"rules": schema.ListAttribute{
+				Computed:    true,
+				Description: "Merged incoming rules, the rule blocks followed by the presets",
+				ElementType: firewallRuleType,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rule": schema.ListNestedBlock{
+				Description: "Additional incoming rules, added before the presets",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"ip_version": schema.StringAttribute{
+							Optional:   true,
+							Validators: []validator.String{stringvalidator.OneOf("ipv4", "ipv6")},
+						},
+						"name":      schema.StringAttribute{Optional: true},
+						"dst_ip":    schema.StringAttribute{Optional: true},
+						"dst_port":  schema.StringAttribute{Optional: true},
+						"src_ip":    schema.StringAttribute{Optional: true},
+						"src_port":  schema.StringAttribute{Optional: true},
+						"protocol":  schema.StringAttribute{Optional: true},
+						"tcp_flags": schema.StringAttribute{Optional: true},
+						"action": schema.StringAttribute{
+							Required:   true,
+							Validators: []validator.String{stringvalidator.OneOf("accept", "discard")},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *firewallRulesetDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var config firewallRulesetDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for idx, rule := range config.Rule {
+		resp.Diagnostics.Append(validateFirewallRule(path.Root("rule").AtListIndex(idx), rule)...)
+	}
+}
+
+func (d *firewallRulesetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config firewallRulesetDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	selected := make(map[string]bool, len(config.Presets))
+	for _, preset := range config.Presets {
+		selected[preset.ValueString()] = true
+	}
+
+	rules := expandFirewallRules(config.Rule)
+	for _, preset := range firewallPresets {
+		if selected[preset.name] {
+			rules = append(rules, preset.rules...)
+		}
+	}
+	rules = uniqueFirewallRules(rules)
+	if len(rules) > maxFirewallRules {
+		resp.Diagnostics.AddError("Too many firewall rules",
+			fmt.Sprintf("Robot accepts at most %d rules per chain, the rule set has %d", maxFirewallRules, len(rules)))
+		return
+	}
+
+	config.ID = types.StringValue("firewall_ruleset")
+	config.Rules = flattenFirewallRules(rules)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
+
+// uniqueFirewallRules drops rules matching the same traffic with the same action as
+// an earlier rule, whatever their name.
+func uniqueFirewallRules(rules []robot.FirewallRule) []robot.FirewallRule {
+	seen := make(map[robot.FirewallRule]bool, len(rules))
+	result := make([]robot.FirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		key := rule
+		key.Name = ""
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, rule)
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package hetznerrobot
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDataFirewallRuleset(t *testing.T) {
+	d := newTestDataSource(t, newFirewallRulesetDataSource(), nil)
+
+	read := func(presets []string, rules []firewallRuleModel) ([]firewallRuleModel, diag.Diagnostics) {
+		config := firewallRulesetDataSourceModel{Rule: rules}
+		for _, preset := range presets {
+			config.Presets = append(config.Presets, types.StringValue(preset))
+		}
+
+		state, diags := d.read(config)
+		if diags.HasError() {
+			return nil, diags
+		}
+		var model firewallRulesetDataSourceModel
+		d.get(state, &model)
+		return model.Rules, nil
+	}
+
+	rules, diags := read([]string{"https", "ssh", "established", "ssh"}, []firewallRuleModel{
+		{Name: types.StringValue("Block scanner"), SrcIP: types.StringValue("198.51.100.7"), Action: types.StringValue("discard")},
+		{Name: types.StringValue("Allow ssh"), DstPort: types.StringValue("22"), Protocol: types.StringValue("tcp"), Action: types.StringValue("accept")},
+	})
+	if diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	var names []string
+	for _, rule := range rules {
+		names = append(names, rule.Name.ValueString())
+	}
+	if want := []string{"Block scanner", "Allow ssh", "established", "https"}; !slices.Equal(names, want) {
+		t.Errorf("expected rules %v, got %v", want, names)
+	}
+
+	rules, diags = read([]string{"hetzner_dns", "hetzner_dns_legacy"}, nil)
+	if diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	names = nil
+	for _, rule := range rules {
+		names = append(names, rule.Name.ValueString())
+	}
+	if want := []string{"hetzner_dns", "hetzner_dns_legacy_1", "hetzner_dns_legacy_2", "hetzner_dns_legacy_3"}; !slices.Equal(names, want) {
+		t.Errorf("expected rules %v, got %v", want, names)
+	}
+	if !firewallAcceptsHetznerDNS(rules[:1]) || !firewallAcceptsHetznerDNS(rules[1:]) {
+		t.Errorf("expected the hetzner_dns presets to accept answers of the resolvers, got %v", rules)
+	}
+
+	// every preset but the legacy resolvers leaves room for user rules
+	var defaultPresets []string
+	for _, preset := range firewallPresets {
+		if preset.name != "hetzner_dns_legacy" {
+			defaultPresets = append(defaultPresets, preset.name)
+		}
+	}
+	if rules, diags = read(defaultPresets, nil); diags.HasError() || len(rules) >= maxFirewallRules {
+		t.Errorf("expected the presets to leave room for user rules, got %d rules %v", len(rules), diags)
+	}
+
+	var userRules []firewallRuleModel
+	for _, port := range []string{"25", "110", "143", "587"} {
+		userRules = append(userRules, firewallRuleModel{DstPort: types.StringValue(port), Protocol: types.StringValue("tcp"), Action: types.StringValue("accept")})
+	}
+	if _, diags := read([]string{"ssh", "http", "https", "icmp", "established", "hetzner_dns", "ntp"}, userRules); !diags.HasError() {
+		t.Error("expected a rule set beyond the Robot limit to fail")
+	}
+}
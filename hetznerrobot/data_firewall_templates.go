@@ -0,0 +1,85 @@
+package hetznerrobot
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+type firewallTemplatesDataSource struct {
+	client *robot.Client
+}
+
+type firewallTemplatesDataSourceModel struct {
+	ID        types.String                   `tfsdk:"id"`
+	Templates []firewallTemplateSummaryModel `tfsdk:"templates"`
+}
+
+type firewallTemplateSummaryModel struct {
+	ID           types.Int64  `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	WhitelistHOS types.Bool   `tfsdk:"whitelist_hos"`
+	FilterIPv6   types.Bool   `tfsdk:"filter_ipv6"`
+	IsDefault    types.Bool   `tfsdk:"is_default"`
+}
+
+func newFirewallTemplatesDataSource() datasource.DataSource {
+	return &firewallTemplatesDataSource{}
+}
+
+func (d *firewallTemplatesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_templates"
+}
+
+func (d *firewallTemplatesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"templates": schema.ListAttribute{
+				Computed:    true,
+				Description: "Firewall templates of the account",
+				ElementType: types.ObjectType{AttrTypes: map[string]attr.Type{
+					"id":            types.Int64Type,
+					"name":          types.StringType,
+					"whitelist_hos": types.BoolType,
+					"filter_ipv6":   types.BoolType,
+					"is_default":    types.BoolType,
+				}},
+			},
+		},
+	}
+}
+
+func (d *firewallTemplatesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = dataSourceClient(req, resp)
+}
+
+func (d *firewallTemplatesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	templates, err := d.client.ListFirewallTemplates(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to list firewall templates", err.Error())
+		return
+	}
+
+	state := firewallTemplatesDataSourceModel{
+		ID:        types.StringValue("firewall_templates"),
+		Templates: make([]firewallTemplateSummaryModel, 0, len(templates)),
+	}
+	for _, template := range templates {
+		state.Templates = append(state.Templates, firewallTemplateSummaryModel{
+			ID:           types.Int64Value(int64(template.ID)),
+			Name:         types.StringValue(template.Name),
+			WhitelistHOS: types.BoolValue(template.WhitelistHetznerServices),
+			FilterIPv6:   types.BoolValue(template.FilterIPv6),
+			IsDefault:    types.BoolValue(template.IsDefault),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
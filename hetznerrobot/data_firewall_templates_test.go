@@ -0,0 +1,33 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestDataFirewallTemplates(t *testing.T) {
+	fake := robotfake.New(t)
+	client := testClient(fake)
+	ctx := context.Background()
+
+	for _, name := range []string{"standard", "web"} {
+		if _, err := client.CreateFirewallTemplate(ctx, robot.FirewallTemplate{Name: name, IsDefault: name == "web"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := newTestDataSource(t, newFirewallTemplatesDataSource(), fake)
+	state, diags := d.read(firewallTemplatesDataSourceModel{})
+	if diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	var model firewallTemplatesDataSourceModel
+	d.get(state, &model)
+	if len(model.Templates) != 2 || model.Templates[0].Name.ValueString() != "standard" || !model.Templates[1].IsDefault.ValueBool() {
+		t.Errorf("unexpected templates: %v", model.Templates)
+	}
+}
@@ -0,0 +1,50 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestDataFirewall(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	client := testClient(fake)
+	ctx := context.Background()
+
+	err := client.SetFirewall(ctx, robot.Firewall{
+		IP:                       "192.0.2.10",
+		WhitelistHetznerServices: true,
+		Status:                   "active",
+		Rules: robot.FirewallRules{
+			Input:  []robot.FirewallRule{{Name: "Allow ssh", DstPort: "22", Protocol: "tcp", Action: "accept"}},
+			Output: []robot.FirewallRule{{Name: "Allow all", Action: "accept"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := newTestDataSource(t, newFirewallDataSource(), fake)
+	for _, config := range []firewallDataSourceModel{
+		{ServerIP: types.StringValue("192.0.2.10"), ServerNumber: types.Int64Null()},
+		{ServerIP: types.StringNull(), ServerNumber: types.Int64Value(321)},
+	} {
+		state, diags := d.read(config)
+		if diags.HasError() {
+			t.Fatalf("read: %v", diags)
+		}
+
+		var model firewallDataSourceModel
+		d.get(state, &model)
+		if model.ServerIP.ValueString() != "192.0.2.10" || model.ServerNumber.ValueInt64() != 321 || model.Status.ValueString() != "active" || model.Port.ValueString() != "main" {
+			t.Errorf("unexpected firewall: %+v", model)
+		}
+		if len(model.Rule) != 1 || model.Rule[0].DstPort.ValueString() != "22" || len(model.OutputRule) != 1 {
+			t.Errorf("unexpected rules: %v %v", model.Rule, model.OutputRule)
+		}
+	}
+}
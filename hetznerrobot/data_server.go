@@ -2,9 +2,11 @@ package hetznerrobot
 
 import (
 	"context"
+	"strconv"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"strconv"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
 func dataServer() *schema.Resource {
@@ -134,11 +136,11 @@ func dataServer() *schema.Resource {
 }
 
 func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*robot.Client)
 
 	serverNumber := d.Get("server_number").(int)
 
-	server, err := c.getServer(ctx, serverNumber)
+	server, err := c.GetServer(ctx, serverNumber)
 	if err != nil {
 		return diag.Errorf("Unable to find Server with IP %d:\n\t %q", serverNumber, err)
 	}
@@ -150,7 +152,7 @@ func dataSourceServerRead(ctx context.Context, d *schema.ResourceData, meta inte
 	d.Set("server_ip", server.ServerIP)
 	d.Set("server_ipv6", server.ServerIPv6)
 	d.Set("server_name", server.ServerName)
-	d.Set("server_subnets", server.Subnets)
+	d.Set("server_subnets", flattenServerSubnets(server.Subnets))
 	d.Set("status", server.Status)
 	d.Set("traffic", server.Traffic)
 	d.Set("linked_storagebox", server.LinkedStoragebox)
@@ -282,23 +284,15 @@ func dataServers() *schema.Resource {
 }
 
 func dataSourceServersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	client := meta.(HetznerRobotClient)
+	client := meta.(*robot.Client)
 
-	servers, err := client.getServers(ctx)
+	servers, err := client.ListServers(ctx)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	serverList := make([]map[string]interface{}, len(servers))
 	for i, server := range servers {
-		subnets := make([]map[string]interface{}, len(server.Subnets))
-		for j, subnet := range server.Subnets {
-			subnets[j] = map[string]interface{}{
-				"ip":   subnet.IP,
-				"mask": subnet.Mask,
-			}
-		}
-
 		serverMap := map[string]interface{}{
 			"server_number":     server.ServerNumber,
 			"server_ip":         server.ServerIP,
@@ -308,7 +302,7 @@ func dataSourceServersRead(ctx context.Context, d *schema.ResourceData, meta int
 			"paid_until":        server.PaidUntil,
 			"product":           server.Product,
 			"ip_addresses":      server.IPs,
-			"server_subnets":    subnets,
+			"server_subnets":    flattenServerSubnets(server.Subnets),
 			"status":            server.Status,
 			"traffic":           server.Traffic,
 			"linked_storagebox": server.LinkedStoragebox,
@@ -333,3 +327,14 @@ func dataSourceServersRead(ctx context.Context, d *schema.ResourceData, meta int
 	var diags diag.Diagnostics
 	return diags
 }
+
+func flattenServerSubnets(serverSubnets []robot.ServerSubnet) []map[string]interface{} {
+	subnets := make([]map[string]interface{}, len(serverSubnets))
+	for i, subnet := range serverSubnets {
+		subnets[i] = map[string]interface{}{
+			"ip":   subnet.IP,
+			"mask": subnet.Mask,
+		}
+	}
+	return subnets
+}
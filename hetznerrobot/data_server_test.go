@@ -0,0 +1,63 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+)
+
+func TestAccDataServer_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "one")
+	fake.AddServer(322, "192.0.2.11", "two")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t, fake),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "hetzner-robot_server" "test" {
+  server_number = 321
+}
+
+data "hetzner-robot_servers" "all" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetzner-robot_server.test", "server_name", "one"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_servers.all", "servers.#", "2"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_servers.all", "servers.1.server_ip", "192.0.2.11"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataServer(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "one")
+	fake.AddServer(322, "192.0.2.11", "two")
+	client := testClient(fake)
+	ctx := context.Background()
+
+	d := schema.TestResourceDataRaw(t, dataServer().Schema, map[string]interface{}{
+		"server_number": 321,
+	})
+	if diags := dataSourceServerRead(ctx, d, client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if d.Id() != "321" || d.Get("server_ip") != "192.0.2.10" || d.Get("server_subnets.0.mask") != "64" {
+		t.Errorf("unexpected server %q: %v", d.Id(), d.Get("server_ip"))
+	}
+
+	d = schema.TestResourceDataRaw(t, dataServers().Schema, map[string]interface{}{})
+	if diags := dataSourceServersRead(ctx, d, client); diags.HasError() {
+		t.Fatalf("read servers: %v", diags)
+	}
+	if d.Get("servers.#") != 2 || d.Get("servers.1.server_ip") != "192.0.2.11" {
+		t.Errorf("unexpected servers: %v", d.Get("servers"))
+	}
+}
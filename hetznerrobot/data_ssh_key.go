@@ -2,8 +2,10 @@ package hetznerrobot
 
 import (
 	"context"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
 func dataSshKey() *schema.Resource {
@@ -46,11 +48,11 @@ func dataSshKey() *schema.Resource {
 }
 
 func dataSourceSshKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*robot.Client)
 
 	keyFingerprint := d.Get("fingerprint").(string)
 
-	key, err := c.getSshKey(ctx, keyFingerprint)
+	key, err := c.GetSSHKey(ctx, keyFingerprint)
 	if err != nil {
 		return diag.Errorf("Unable to find SSH key %q:\n\t %q", keyFingerprint, err)
 	}
@@ -0,0 +1,60 @@
+package hetznerrobot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+)
+
+func TestAccDataSshKey_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	fake := robotfake.New(t)
+	client := testClient(fake)
+	if _, err := client.CreateSSHKey(context.Background(), "deploy", testSshKeyData); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t, fake),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "hetzner-robot_ssh_key" "test" {
+  fingerprint = "` + testSshKeyFingerprint + `"
+}`,
+				Check: resource.TestCheckResourceAttr("data.hetzner-robot_ssh_key.test", "name", "deploy"),
+			},
+		},
+	})
+}
+
+func TestDataSshKey(t *testing.T) {
+	fake := robotfake.New(t)
+	client := testClient(fake)
+	ctx := context.Background()
+
+	if _, err := client.CreateSSHKey(ctx, "deploy", testSshKeyData); err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSshKey().Schema, map[string]interface{}{
+		"fingerprint": testSshKeyFingerprint,
+	})
+	if diags := dataSourceSshKeyRead(ctx, d, client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if d.Id() != testSshKeyFingerprint || d.Get("name") != "deploy" || d.Get("data") != testSshKeyData {
+		t.Errorf("unexpected key %q: %v", d.Id(), d.Get("name"))
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSshKey().Schema, map[string]interface{}{
+		"fingerprint": "00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00",
+	})
+	if diags := dataSourceSshKeyRead(ctx, d, client); !diags.HasError() {
+		t.Error("expected an unknown key to fail")
+	}
+}
@@ -2,14 +2,23 @@ package hetznerrobot
 
 import (
 	"context"
+	"strconv"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
 func dataVSwitch() *schema.Resource {
 	return &schema.Resource{
+		Description: "The vSwitch selected by `id`.",
 		ReadContext: dataSourceVSwitchRead,
 		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "vSwitch ID",
+			},
 			"name": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -101,10 +110,14 @@ func dataVSwitch() *schema.Resource {
 }
 
 func dataSourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+	c := meta.(*robot.Client)
 
-	vSwitchID := d.Id()
-	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
+	vSwitchID := d.Get("id").(string)
+	id, err := strconv.Atoi(vSwitchID)
+	if err != nil {
+		return diag.Errorf("Invalid VSwitch ID %q: %s", vSwitchID, err)
+	}
+	vSwitch, err := c.GetVSwitch(ctx, id)
 	if err != nil {
 		return diag.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err)
 	}
@@ -112,9 +125,9 @@ func dataSourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta int
 	d.Set("name", vSwitch.Name)
 	d.Set("vlan", vSwitch.Vlan)
 	d.Set("is_cancelled", vSwitch.Cancelled)
-	d.Set("servers", vSwitch.Server)
-	d.Set("subnets", vSwitch.Subnet)
-	d.Set("cloud_networks", vSwitch.CloudNetwork)
+	d.Set("servers", flattenVSwitchServers(vSwitch.Server))
+	d.Set("subnets", flattenVSwitchSubnets(vSwitch.Subnet))
+	d.Set("cloud_networks", flattenVSwitchCloudNetworks(vSwitch.CloudNetwork))
 	d.SetId(vSwitchID)
 
 	// Warning or errors can be collected in a slice type
@@ -122,3 +135,41 @@ func dataSourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta int
 
 	return diags
 }
+
+func flattenVSwitchServers(servers []robot.VSwitchServer) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(servers))
+	for _, server := range servers {
+		result = append(result, map[string]interface{}{
+			"server_number":   server.ServerNumber,
+			"server_ip":       server.ServerIP,
+			"server_ipv6_net": server.ServerIPv6Net,
+			"status":          server.Status,
+		})
+	}
+	return result
+}
+
+func flattenVSwitchSubnets(subnets []robot.VSwitchSubnet) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(subnets))
+	for _, subnet := range subnets {
+		result = append(result, map[string]interface{}{
+			"ip":      subnet.IP,
+			"mask":    subnet.Mask,
+			"gateway": subnet.Gateway,
+		})
+	}
+	return result
+}
+
+func flattenVSwitchCloudNetworks(cloudNetworks []robot.VSwitchCloudNetwork) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(cloudNetworks))
+	for _, cloudNetwork := range cloudNetworks {
+		result = append(result, map[string]interface{}{
+			"id":      cloudNetwork.ID,
+			"ip":      cloudNetwork.IP,
+			"mask":    cloudNetwork.Mask,
+			"gateway": cloudNetwork.Gateway,
+		})
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestAccDataVSwitch_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	fake := robotfake.New(t)
+	client := testClient(fake)
+	vSwitch, err := client.CreateVSwitch(context.Background(), "private", 4010)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t, fake),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "hetzner-robot_vswitch" "test" {
+  id = "` + strconv.Itoa(vSwitch.ID) + `"
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.hetzner-robot_vswitch.test", "name", "private"),
+					resource.TestCheckResourceAttr("data.hetzner-robot_vswitch.test", "vlan", "4010"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataVSwitch(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "one")
+	client := testClient(fake)
+	ctx := context.Background()
+
+	vSwitch, err := client.CreateVSwitch(ctx, "private", 4010)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vSwitchID := strconv.Itoa(vSwitch.ID)
+	if err := client.AddVSwitchServers(ctx, vSwitch.ID, []robot.VSwitchServer{{ServerNumber: 321}}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, dataVSwitch().Schema, map[string]interface{}{
+		"id": vSwitchID,
+	})
+	if diags := dataSourceVSwitchRead(ctx, d, client); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	if d.Get("name") != "private" || d.Get("servers.0.server_number") != 321 || d.Get("servers.0.status") != "ready" {
+		t.Errorf("unexpected vSwitch: %v", d.Get("servers"))
+	}
+}
@@ -0,0 +1,103 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// apiFieldPath maps a Robot input field name (e.g. "rules[input][0][src_ip]") to the
+// attribute path of the resource it originates from. An empty path attaches the
+// diagnostic to the resource as a whole.
+type apiFieldPath func(field []string) path.Path
+
+// apiErrorDiagnostics converts an error returned by the client into diagnostics.
+// Missing and invalid input fields reported by Robot get a diagnostic each, pointing at
+// the offending attribute.
+func apiErrorDiagnostics(summary string, err error, fieldPath apiFieldPath) diag.Diagnostics {
+	var apiError *robot.APIError
+	if !errors.As(err, &apiError) || (len(apiError.Missing) == 0 && len(apiError.Invalid) == 0) {
+		return diag.Diagnostics{diag.NewErrorDiagnostic(summary, err.Error())}
+	}
+
+	var diags diag.Diagnostics
+	for _, field := range apiError.Missing {
+		diags.Append(apiFieldDiagnostic(summary, fmt.Sprintf("Hetzner webservice reports a missing value for %q: %s", field, apiError.Message), field, fieldPath))
+	}
+	for _, field := range apiError.Invalid {
+		diags.Append(apiFieldDiagnostic(summary, fmt.Sprintf("Hetzner webservice reports an invalid value for %q: %s", field, apiError.Message), field, fieldPath))
+	}
+	return diags
+}
+
+// removeGoneResource removes a resource Robot no longer knows about, e.g. deleted
+// outside Terraform or belonging to a cancelled server, from the state, so Terraform
+// plans to recreate it instead of failing.
+func removeGoneResource(ctx context.Context, state *tfsdk.State, kind string, id string, err error) diag.Diagnostics {
+	state.RemoveResource(ctx)
+
+	return diag.Diagnostics{diag.NewWarningDiagnostic(
+		fmt.Sprintf("%s %s not found, removing it from state", kind, id),
+		err.Error(),
+	)}
+}
+
+func apiFieldDiagnostic(summary string, detail string, field string, fieldPath apiFieldPath) diag.Diagnostic {
+	if fieldPath != nil {
+		if attributePath := fieldPath(splitAPIField(field)); !attributePath.Equal(path.Empty()) {
+			return diag.NewAttributeErrorDiagnostic(attributePath, summary, detail)
+		}
+	}
+	return diag.NewErrorDiagnostic(summary, detail)
+}
+
+// splitAPIField splits "rules[input][0][src_ip]" into ["rules", "input", "0", "src_ip"].
+func splitAPIField(field string) []string {
+	field = strings.ReplaceAll(field, "]", "")
+	return strings.Split(field, "[")
+}
+
+// apiFieldMap builds an apiFieldPath for flat resources from a Robot field to attribute name mapping.
+func apiFieldMap(fields map[string]string) apiFieldPath {
+	return func(field []string) path.Path {
+		if attribute, ok := fields[field[0]]; ok {
+			return path.Root(attribute)
+		}
+		return path.Empty()
+	}
+}
+
+// firewallFieldMap builds an apiFieldPath for firewalls and firewall templates, mapping
+// their flat fields like apiFieldMap and their rules to the rule and output_rule blocks.
+func firewallFieldMap(fields map[string]string) apiFieldPath {
+	flatFieldPath := apiFieldMap(fields)
+	return func(field []string) path.Path {
+		if field[0] != "rules" {
+			return flatFieldPath(field)
+		}
+
+		attributePath := path.Root("rule")
+		if len(field) > 1 && field[1] == "output" {
+			attributePath = path.Root("output_rule")
+		}
+		if len(field) < 3 {
+			return attributePath
+		}
+		idx, err := strconv.Atoi(field[2])
+		if err != nil {
+			return attributePath
+		}
+		attributePath = attributePath.AtListIndex(idx)
+		if len(field) > 3 {
+			attributePath = attributePath.AtName(field[3])
+		}
+		return attributePath
+	}
+}
@@ -0,0 +1,340 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// maxFirewallRules is the number of rules Robot accepts per chain.
+const maxFirewallRules = 10
+
+// firewallTCPFlagsPattern matches flags combined with either | or &, e.g. syn|fin.
+var firewallTCPFlagsPattern = regexp.MustCompile(`^(?:syn|fin|rst|psh|urg|ack)(?:(?:\|(?:syn|fin|rst|psh|urg|ack))*|(?:&(?:syn|fin|rst|psh|urg|ack))*)$`)
+
+// hetznerResolvers are the networks of the DNS resolvers of Hetzner, 185.12.64.1 and
+// .2, whose answers the hetzner_dns preset accepts.
+var hetznerResolvers = []netip.Prefix{
+	netip.MustParsePrefix("185.12.64.0/24"),
+}
+
+// hetznerLegacyResolvers are the older resolvers still configured on many servers,
+// whose answers the hetzner_dns_legacy preset accepts.
+var hetznerLegacyResolvers = []netip.Prefix{
+	netip.MustParsePrefix("213.133.98.98/32"),
+	netip.MustParsePrefix("213.133.99.99/32"),
+	netip.MustParsePrefix("213.133.100.100/32"),
+}
+
+// validateFirewallRuleBlocks validates the rule and output_rule blocks and the named
+// rules of config.
+func validateFirewallRuleBlocks(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var input []firewallRuleModel
+	inputKnown := true
+
+	for _, block := range []string{"rule", "output_rule"} {
+		var rules types.List
+		diags.Append(config.GetAttribute(ctx, path.Root(block), &rules)...)
+		if diags.HasError() {
+			return diags
+		}
+		if rules.IsUnknown() {
+			inputKnown = inputKnown && block != "rule"
+			continue
+		}
+
+		if len(rules.Elements()) > maxFirewallRules {
+			diags.AddAttributeError(path.Root(block).AtListIndex(maxFirewallRules), "Too many firewall rules",
+				fmt.Sprintf("Robot accepts at most %d rules per chain, got %d %s blocks", maxFirewallRules, len(rules.Elements()), block))
+		}
+		for idx, element := range rules.Elements() {
+			object, ok := element.(types.Object)
+			if !ok || object.IsUnknown() {
+				inputKnown = inputKnown && block != "rule"
+				continue
+			}
+			var rule firewallRuleModel
+			diags.Append(object.As(ctx, &rule, basetypes.ObjectAsOptions{})...)
+			diags.Append(validateFirewallRule(path.Root(block).AtListIndex(idx), rule)...)
+			if block == "rule" {
+				input = append(input, rule)
+			}
+		}
+	}
+
+	for _, attribute := range []string{"named_rule", "named_output_rule"} {
+		if _, ok := config.Schema.GetAttributes()[attribute]; !ok {
+			continue
+		}
+		var rules types.Map
+		diags.Append(config.GetAttribute(ctx, path.Root(attribute), &rules)...)
+		if diags.HasError() {
+			return diags
+		}
+		if rules.IsUnknown() {
+			inputKnown = inputKnown && attribute != "named_rule"
+			continue
+		}
+
+		if len(rules.Elements()) > maxFirewallRules {
+			diags.AddAttributeError(path.Root(attribute), "Too many firewall rules",
+				fmt.Sprintf("Robot accepts at most %d rules per chain, got %d %s", maxFirewallRules, len(rules.Elements()), attribute))
+		}
+		elements := rules.Elements()
+		names := make([]string, 0, len(elements))
+		for name := range elements {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		named := make(map[string]firewallNamedRuleModel)
+		priorities := make(map[int64]string)
+		for _, name := range names {
+			object, ok := elements[name].(types.Object)
+			if !ok || object.IsUnknown() {
+				inputKnown = inputKnown && attribute != "named_rule"
+				continue
+			}
+			var rule firewallNamedRuleModel
+			diags.Append(object.As(ctx, &rule, basetypes.ObjectAsOptions{})...)
+			rulePath := path.Root(attribute).AtMapKey(name)
+			diags.Append(validateFirewallRule(rulePath, rule.rule(name))...)
+			if name == "" {
+				diags.AddAttributeError(rulePath, "Invalid firewall rule name",
+					"named rules are keyed by their name in Robot, the name must not be empty")
+			}
+
+			if priority := rule.Priority.ValueInt64(); !rule.Priority.IsNull() && !rule.Priority.IsUnknown() {
+				if other, ok := priorities[priority]; ok {
+					diags.AddAttributeError(rulePath.AtName("priority"), "Duplicate firewall rule priority",
+						fmt.Sprintf("priority %d of rule %q is already used by rule %q", priority, name, other))
+				}
+				priorities[priority] = name
+			} else {
+				inputKnown = inputKnown && attribute != "named_rule"
+			}
+			named[name] = rule
+		}
+		if attribute == "named_rule" {
+			input = append(input, firewallChainRules(nil, named)...)
+		}
+	}
+
+	var whitelistHOS types.Bool
+	diags.Append(config.GetAttribute(ctx, path.Root("whitelist_hos"), &whitelistHOS)...)
+	if diags.HasError() || !inputKnown || whitelistHOS.IsNull() || whitelistHOS.IsUnknown() || whitelistHOS.ValueBool() {
+		return diags
+	}
+	if len(input) > 0 && !firewallAcceptsHetznerDNS(input) {
+		// only a warning, a server using other resolvers does not need their answers
+		diags.AddAttributeWarning(path.Root("whitelist_hos"), "Firewall discards Hetzner DNS answers",
+			"With whitelist_hos = false no rule accepts DNS answers from the Hetzner resolvers, the firewall discards "+
+				"them with every other packet not matching a rule. Set whitelist_hos = true or accept udp traffic from source port 53, "+
+				"unless the server uses other resolvers.")
+	}
+	return diags
+}
+
+// validateFirewallRule checks the syntax of the addresses, ports and TCP flags of a
+// rule, and that they fit its protocol.
+func validateFirewallRule(rulePath path.Path, rule firewallRuleModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, field := range []struct {
+		name  string
+		value types.String
+	}{{"src_ip", rule.SrcIP}, {"dst_ip", rule.DstIP}} {
+		if isSet(field.value) && ipFamily(field.value.ValueString()) == "" {
+			diags.AddAttributeError(rulePath.AtName(field.name), "Invalid firewall rule",
+				fmt.Sprintf("%s %q is not an IP address or CIDR network", field.name, field.value.ValueString()))
+		}
+	}
+	if !diags.HasError() {
+		diags.Append(validateFirewallRuleIPVersion(rulePath, rule)...)
+	}
+
+	protocol := rule.Protocol.ValueString()
+	for _, field := range []struct {
+		name  string
+		value types.String
+	}{{"src_port", rule.SrcPort}, {"dst_port", rule.DstPort}} {
+		if !isSet(field.value) {
+			continue
+		}
+		if _, _, ok := parsePortRange(field.value.ValueString()); !ok {
+			diags.AddAttributeError(rulePath.AtName(field.name), "Invalid firewall rule",
+				fmt.Sprintf("%s %q is not a port or port range like 32768-65535", field.name, field.value.ValueString()))
+		}
+		// Robot is not known to reject ports of rules matching every protocol
+		if rule.Protocol.IsNull() {
+			diags.AddAttributeWarning(rulePath.AtName(field.name), "Firewall rule port without protocol",
+				fmt.Sprintf("%s only applies to tcp and udp traffic, set protocol to tcp or udp", field.name))
+		} else if !rule.Protocol.IsUnknown() && protocol != "tcp" && protocol != "udp" {
+			diags.AddAttributeError(rulePath.AtName(field.name), "Invalid firewall rule",
+				fmt.Sprintf("%s is only supported by tcp and udp rules", field.name))
+		}
+	}
+
+	if isSet(rule.TCPFlags) {
+		if !firewallTCPFlagsPattern.MatchString(rule.TCPFlags.ValueString()) {
+			diags.AddAttributeError(rulePath.AtName("tcp_flags"), "Invalid firewall rule",
+				fmt.Sprintf("tcp_flags %q is not a list of syn, fin, rst, psh, urg or ack joined by either | or &", rule.TCPFlags.ValueString()))
+		}
+		if !rule.Protocol.IsUnknown() && protocol != "tcp" {
+			diags.AddAttributeError(rulePath.AtName("tcp_flags"), "Invalid firewall rule",
+				"tcp_flags is only supported by tcp rules")
+		}
+	}
+	return diags
+}
+
+// validateFirewallRuleIPVersion checks that the addresses of a rule belong to its
+// ip_version, or to the same family when it is left empty.
+func validateFirewallRuleIPVersion(rulePath path.Path, rule firewallRuleModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	ipVersion := rule.IPVersion.ValueString()
+	if rule.IPVersion.IsUnknown() {
+		return diags
+	}
+	for _, field := range []struct {
+		name  string
+		value types.String
+	}{{"src_ip", rule.SrcIP}, {"dst_ip", rule.DstIP}} {
+		family := ipFamily(field.value.ValueString())
+		if family == "" {
+			continue
+		}
+		if ipVersion == "" {
+			ipVersion = family
+			continue
+		}
+		if family != ipVersion {
+			diags.AddAttributeError(rulePath.AtName(field.name), "Invalid firewall rule",
+				fmt.Sprintf("%s %q is not an %s address", field.name, field.value.ValueString(), ipVersion))
+		}
+	}
+	return diags
+}
+
+// firewallAcceptsHetznerDNS reports whether the input rules accept answers of any
+// Hetzner resolver, the first matching rule decides like it does in Robot.
+func firewallAcceptsHetznerDNS(rules []firewallRuleModel) bool {
+	for _, rule := range rules {
+		if !firewallRuleKnown(rule) {
+			return true
+		}
+	}
+
+	for _, resolvers := range slices.Concat(hetznerResolvers, hetznerLegacyResolvers) {
+		for _, rule := range rules {
+			// a rule only decides for the resolvers of a network when it covers all of
+			// them, answers of the others go on to the next rule
+			if !firewallRuleMatchesDNSAnswer(rule, resolvers) {
+				continue
+			}
+			if rule.Action.ValueString() == "accept" {
+				return true
+			}
+			break
+		}
+	}
+	return false
+}
+
+// firewallRuleMatchesDNSAnswer reports whether rule matches udp packets from port 53
+// of every resolver in network to any ephemeral port.
+func firewallRuleMatchesDNSAnswer(rule firewallRuleModel, resolvers netip.Prefix) bool {
+	if ipVersion := rule.IPVersion.ValueString(); ipVersion != "" && ipVersion != "ipv4" {
+		return false
+	}
+	if protocol := rule.Protocol.ValueString(); protocol != "" && protocol != "udp" {
+		return false
+	}
+	if isSet(rule.TCPFlags) {
+		return false
+	}
+	if isSet(rule.SrcPort) {
+		if low, high, _ := parsePortRange(rule.SrcPort.ValueString()); low > 53 || high < 53 {
+			return false
+		}
+	}
+	if isSet(rule.DstPort) {
+		// the Linux default of net.ipv4.ip_local_port_range
+		if low, high, _ := parsePortRange(rule.DstPort.ValueString()); low > 32768 || high < 60999 {
+			return false
+		}
+	}
+	if !isSet(rule.SrcIP) {
+		return true
+	}
+
+	prefix, err := netip.ParsePrefix(rule.SrcIP.ValueString())
+	if err != nil {
+		addr, err := netip.ParseAddr(rule.SrcIP.ValueString())
+		if err != nil {
+			return false
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	return prefix.Bits() <= resolvers.Bits() && prefix.Contains(resolvers.Addr())
+}
+
+// firewallRuleKnown reports whether none of the attributes of rule is unknown.
+func firewallRuleKnown(rule firewallRuleModel) bool {
+	for _, value := range []types.String{rule.IPVersion, rule.DstIP, rule.DstPort, rule.SrcIP, rule.SrcPort, rule.Protocol, rule.TCPFlags, rule.Action} {
+		if value.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePortRange parses a port like 443 or a port range like 32768-65535.
+func parsePortRange(value string) (int, int, bool) {
+	ports := strings.SplitN(value, "-", 2)
+	bounds := make([]int, 0, 2)
+	for _, port := range ports {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 0 || n > 65535 || strings.TrimLeft(port, "0123456789") != "" {
+			return 0, 0, false
+		}
+		bounds = append(bounds, n)
+	}
+	low, high := bounds[0], bounds[len(bounds)-1]
+	return low, high, low <= high
+}
+
+// ipFamily returns "ipv4" or "ipv6" for an address or network, or "" when value is
+// neither.
+func ipFamily(value string) string {
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return ""
+		}
+		addr = prefix.Addr()
+	}
+	if addr.Is4() {
+		return "ipv4"
+	}
+	return "ipv6"
+}
+
+// isSet reports whether value is known and not null.
+func isSet(value types.String) bool {
+	return !value.IsNull() && !value.IsUnknown()
+}
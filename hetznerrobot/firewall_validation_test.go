@@ -0,0 +1,192 @@
+package hetznerrobot
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+)
+
+func TestValidateFirewallRule(t *testing.T) {
+	rulePath := path.Root("rule").AtListIndex(2)
+	tcp := types.StringValue("tcp")
+
+	for _, tc := range []struct {
+		rule firewallRuleModel
+		want []string
+	}{
+		{rule: firewallRuleModel{SrcIP: types.StringValue("198.51.100.0/24"), DstIP: types.StringValue("192.0.2.1")}},
+		{rule: firewallRuleModel{Protocol: tcp, SrcPort: types.StringValue("32768-65535"), DstPort: types.StringValue("443")}},
+		{rule: firewallRuleModel{Protocol: types.StringValue("udp"), DstPort: types.StringValue("53")}},
+		{rule: firewallRuleModel{Protocol: tcp, TCPFlags: types.StringValue("syn|fin|ack")}},
+		{rule: firewallRuleModel{Protocol: tcp, TCPFlags: types.StringValue("syn&ack")}},
+		{rule: firewallRuleModel{Protocol: types.StringUnknown(), DstPort: types.StringValue("22"), TCPFlags: types.StringValue("syn")}},
+		{rule: firewallRuleModel{SrcIP: types.StringValue("198.51.100.0/33")}, want: []string{"src_ip"}},
+		{rule: firewallRuleModel{DstIP: types.StringValue("example.com")}, want: []string{"dst_ip"}},
+		{rule: firewallRuleModel{Protocol: tcp, DstPort: types.StringValue("65536")}, want: []string{"dst_port"}},
+		{rule: firewallRuleModel{Protocol: tcp, SrcPort: types.StringValue("1024-80")}, want: []string{"src_port"}},
+		{rule: firewallRuleModel{Protocol: tcp, DstPort: types.StringValue("80,443")}, want: []string{"dst_port"}},
+		{rule: firewallRuleModel{Protocol: types.StringValue("icmp"), DstPort: types.StringValue("22")}, want: []string{"dst_port"}},
+		{rule: firewallRuleModel{SrcPort: types.StringValue("22")}, want: []string{"src_port"}},
+		{rule: firewallRuleModel{Protocol: types.StringValue("udp"), TCPFlags: types.StringValue("syn")}, want: []string{"tcp_flags"}},
+		{rule: firewallRuleModel{Protocol: tcp, TCPFlags: types.StringValue("syn|ack&fin")}, want: []string{"tcp_flags"}},
+		{rule: firewallRuleModel{Protocol: tcp, TCPFlags: types.StringValue("SYN")}, want: []string{"tcp_flags"}},
+	} {
+		diags := validateFirewallRule(rulePath, tc.rule)
+		if len(diags) != len(tc.want) {
+			t.Errorf("%v: expected %d errors, got %v", tc.rule, len(tc.want), diags)
+			continue
+		}
+		for i, field := range tc.want {
+			if got := diags[i].(diag.DiagnosticWithPath).Path(); !got.Equal(rulePath.AtName(field)) {
+				t.Errorf("%v: expected an error at %s, got %s", tc.rule, rulePath.AtName(field), got)
+			}
+		}
+	}
+}
+
+func TestValidateFirewallRuleIPVersion(t *testing.T) {
+	rulePath := path.Root("rule").AtListIndex(0)
+
+	for _, tc := range []struct {
+		rule firewallRuleModel
+		want path.Path
+	}{
+		{rule: firewallRuleModel{IPVersion: types.StringValue("ipv4"), SrcIP: types.StringValue("198.51.100.1"), DstIP: types.StringValue("192.0.2.0/24")}},
+		{rule: firewallRuleModel{IPVersion: types.StringValue("ipv6"), DstIP: types.StringValue("2001:db8::1")}},
+		{rule: firewallRuleModel{SrcIP: types.StringValue("2001:db8::/32")}},
+		{rule: firewallRuleModel{}},
+		{rule: firewallRuleModel{IPVersion: types.StringUnknown(), SrcIP: types.StringValue("2001:db8::/32")}},
+		{
+			rule: firewallRuleModel{IPVersion: types.StringValue("ipv4"), SrcIP: types.StringValue("2001:db8::/32")},
+			want: rulePath.AtName("src_ip"),
+		},
+		{
+			rule: firewallRuleModel{IPVersion: types.StringValue("ipv6"), DstIP: types.StringValue("192.0.2.1")},
+			want: rulePath.AtName("dst_ip"),
+		},
+		{
+			rule: firewallRuleModel{SrcIP: types.StringValue("192.0.2.1"), DstIP: types.StringValue("2001:db8::1")},
+			want: rulePath.AtName("dst_ip"),
+		},
+	} {
+		diags := validateFirewallRuleIPVersion(rulePath, tc.rule)
+		if len(tc.want.Steps()) == 0 {
+			if diags.HasError() {
+				t.Errorf("%v: unexpected error %v", tc.rule, diags)
+			}
+			continue
+		}
+		if len(diags) != 1 || !diags[0].(diag.DiagnosticWithPath).Path().Equal(tc.want) {
+			t.Errorf("%v: expected an error at %s, got %v", tc.rule, tc.want, diags)
+		}
+	}
+}
+
+func TestValidateFirewallRuleBlocks(t *testing.T) {
+	r := newTestResource(t, newFirewallResource(), robotfake.New(t))
+	accept := firewallRuleModel{Action: types.StringValue("accept")}
+	ssh := firewallRuleModel{Protocol: types.StringValue("tcp"), DstPort: types.StringValue("22"), Action: types.StringValue("accept")}
+	dns := firewallRuleModel{
+		Protocol: types.StringValue("udp"),
+		SrcIP:    types.StringValue("185.12.64.0/24"),
+		SrcPort:  types.StringValue("53"),
+		DstPort:  types.StringValue("32768-65535"),
+		Action:   types.StringValue("accept"),
+	}
+	discard := firewallRuleModel{Action: types.StringValue("discard")}
+	olderDNS := dns
+	olderDNS.SrcIP = types.StringValue("213.133.98.98")
+	discardResolver := firewallRuleModel{SrcIP: types.StringValue("185.12.64.1"), Action: types.StringValue("discard")}
+	oneResolver := dns
+	oneResolver.SrcIP = types.StringValue("185.12.64.1")
+	anyProtocol := firewallRuleModel{DstPort: types.StringValue("22"), Action: types.StringValue("accept")}
+
+	tooMany := make([]firewallRuleModel, maxFirewallRules+1)
+	for idx := range tooMany {
+		tooMany[idx] = accept
+	}
+
+	for _, tc := range []struct {
+		name         string
+		whitelistHOS bool
+		rules        []firewallRuleModel
+		want         []path.Path
+		warning      bool
+	}{
+		{name: "whitelisted", whitelistHOS: true, rules: []firewallRuleModel{ssh}},
+		{name: "accept all", rules: []firewallRuleModel{ssh, accept}},
+		{name: "dns answers", rules: []firewallRuleModel{ssh, dns}},
+		{name: "locked out", rules: []firewallRuleModel{ssh}, want: []path.Path{path.Root("whitelist_hos")}, warning: true},
+		{name: "discarded first", rules: []firewallRuleModel{discard, dns}, want: []path.Path{path.Root("whitelist_hos")}, warning: true},
+		{name: "older resolver", rules: []firewallRuleModel{ssh, olderDNS}},
+		{name: "one resolver discarded", rules: []firewallRuleModel{discardResolver, dns}},
+		{name: "one resolver accepted", rules: []firewallRuleModel{ssh, oneResolver}, want: []path.Path{path.Root("whitelist_hos")}, warning: true},
+		{name: "port without protocol", whitelistHOS: true, rules: []firewallRuleModel{anyProtocol}, want: []path.Path{path.Root("rule").AtListIndex(0).AtName("dst_port")}, warning: true},
+		{name: "too many", whitelistHOS: true, rules: tooMany, want: []path.Path{path.Root("rule").AtListIndex(maxFirewallRules)}},
+	} {
+		diags := r.validate(firewallResourceModel{
+			Timeouts:     testFirewallTimeouts,
+			ServerIP:     types.StringValue("192.0.2.10"),
+			WhitelistHOS: types.BoolValue(tc.whitelistHOS),
+			Rule:         tc.rules,
+		})
+		if len(diags) != len(tc.want) {
+			t.Errorf("%s: expected %d diagnostics, got %v", tc.name, len(tc.want), diags)
+			continue
+		}
+		for i, want := range tc.want {
+			if got := diags[i].(diag.DiagnosticWithPath).Path(); !got.Equal(want) {
+				t.Errorf("%s: expected a diagnostic at %s, got %s", tc.name, want, got)
+			}
+		}
+		if len(diags) > 0 && diags.HasError() == tc.warning {
+			t.Errorf("%s: expected warning %t, got %v", tc.name, tc.warning, diags)
+		}
+	}
+}
+
+func TestValidateFirewallNamedRules(t *testing.T) {
+	r := newTestResource(t, newFirewallResource(), robotfake.New(t))
+
+	for _, tc := range []struct {
+		name  string
+		rules map[string]firewallNamedRuleModel
+		want  path.Path
+	}{
+		{name: "unique", rules: map[string]firewallNamedRuleModel{"one": testNamedRule(10, firewallNamedRuleModel{}), "two": testNamedRule(20, firewallNamedRuleModel{})}},
+		{
+			name:  "duplicate priority",
+			rules: map[string]firewallNamedRuleModel{"one": testNamedRule(10, firewallNamedRuleModel{}), "two": testNamedRule(10, firewallNamedRuleModel{})},
+			want:  path.Root("named_rule").AtMapKey("two").AtName("priority"),
+		},
+		{
+			name:  "empty name",
+			rules: map[string]firewallNamedRuleModel{"": testNamedRule(10, firewallNamedRuleModel{})},
+			want:  path.Root("named_rule").AtMapKey(""),
+		},
+		{
+			name:  "invalid port",
+			rules: map[string]firewallNamedRuleModel{"one": testNamedRule(10, firewallNamedRuleModel{DstPort: types.StringValue("http"), Protocol: types.StringValue("tcp")})},
+			want:  path.Root("named_rule").AtMapKey("one").AtName("dst_port"),
+		},
+	} {
+		diags := r.validate(firewallResourceModel{
+			Timeouts:     testFirewallTimeouts,
+			ServerIP:     types.StringValue("192.0.2.10"),
+			WhitelistHOS: types.BoolValue(true),
+			NamedRule:    tc.rules,
+		})
+		if len(tc.want.Steps()) == 0 {
+			if diags.HasError() {
+				t.Errorf("%s: unexpected error %v", tc.name, diags)
+			}
+			continue
+		}
+		if len(diags) != 1 || !diags[0].(diag.DiagnosticWithPath).Path().Equal(tc.want) {
+			t.Errorf("%s: expected a diagnostic at %s, got %v", tc.name, tc.want, diags)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package hetznerrobot
+
+import "sync"
+
+// firewallLocks serializes firewall changes per server. Robot rejects a change with
+// FIREWALL_IN_PROCESS while the previous one is applied, which parallel applies of
+// e.g. a firewall and its template would otherwise run into.
+var firewallLocks = newKeyedMutex()
+
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: make(map[string]*sync.Mutex)}
+}
+
+// Lock locks key and returns the function unlocking it.
+func (m *keyedMutex) Lock(key string) func() {
+	m.mu.Lock()
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.locks[key] = lock
+	}
+	m.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
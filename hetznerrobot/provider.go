@@ -2,13 +2,31 @@ package hetznerrobot
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/recorder"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
+// New returns the provider factory for the given provider version
+func New(version string) func() *schema.Provider {
+	return func() *schema.Provider {
+		p := Provider()
+		p.ConfigureContextFunc = providerConfigure(p, version, newClientCache())
+		return p
+	}
+}
+
 // Provider -
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"username": {
 				Type:        schema.TypeString,
@@ -23,14 +41,73 @@ func Provider() *schema.Provider {
 			"url": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_URL", "https://robot-ws.your-server.de"),
+				DefaultFunc: schema.EnvDefaultFunc("HETZNERROBOT_URL", robot.DefaultBaseURL),
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      robot.DefaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of retries for rate limited, failed or unreachable Hetzner webservice requests",
+			},
+			"retry_wait_min": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      robot.DefaultRetryWaitMin.String(),
+				ValidateFunc: validateDuration,
+				Description:  "Minimum wait between two retries, as a duration (e.g. \"1s\")",
+			},
+			"retry_wait_max": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      robot.DefaultRetryWaitMax.String(),
+				ValidateFunc: validateDuration,
+				Description:  "Maximum wait between two retries, as a duration (e.g. \"30s\")",
+			},
+			"rate_limits": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "Hourly request budget per endpoint family (e.g. `firewall = 200`), overriding the documented Robot limits. 0 disables throttling for the family",
+				Elem: &schema.Schema{
+					Type:         schema.TypeInt,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+			},
+			"log_masked_keys": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Additional form and JSON keys whose values are masked in debug logs, on top of passwords, SSH key material and the Authorization header",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      robot.DefaultRequestTimeout.String(),
+				ValidateFunc: validateDuration,
+				Description:  "Timeout of a single Hetzner webservice request, as a duration (e.g. \"60s\")",
+			},
+			"http_proxy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithScheme([]string{"http", "https", "socks5"}),
+				Description:  "Proxy used to reach the Hetzner webservice. Defaults to the HTTPS_PROXY / NO_PROXY environment variables",
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to a PEM encoded CA certificate trusted in addition to the system roots",
+			},
+			"ca_cert_pem": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM encoded CA certificate trusted in addition to the system roots",
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip TLS certificate verification. Only meant for local stand-ins of the webservice",
 			},
-		},
-		ResourcesMap: map[string]*schema.Resource{
-			"hetzner-robot_boot":     resourceBoot(),
-			"hetzner-robot_firewall": resourceFirewall(),
-			"hetzner-robot_ssh_key":  resourceSshKey(),
-			"hetzner-robot_vswitch":  resourceVSwitch(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"hetzner-robot_boot":    dataBoot(),
@@ -39,16 +116,167 @@ func Provider() *schema.Provider {
 			"hetzner-robot_ssh_key": dataSshKey(),
 			"hetzner-robot_vswitch": dataVSwitch(),
 		},
-		ConfigureContextFunc: providerConfigure,
+	}
+	p.ConfigureContextFunc = providerConfigure(p, "dev", newClientCache())
+	return p
+}
+
+func providerConfigure(p *schema.Provider, version string, clients *clientCache) schema.ConfigureContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		client, err := clients.client(ctx, sdkProviderConfig(d), p.UserAgent("terraform-provider-hetzner-robot", version))
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		return client, nil
+	}
+}
+
+// providerConfig is the provider configuration, read by the SDK as well as the
+// framework half of the provider.
+type providerConfig struct {
+	username           string
+	password           string
+	url                string
+	maxRetries         int
+	retryWaitMin       string
+	retryWaitMax       string
+	rateLimits         map[string]int
+	logMaskedKeys      []string
+	requestTimeout     string
+	httpProxy          string
+	caCertFile         string
+	caCertPEM          string
+	insecureSkipVerify bool
+}
+
+func sdkProviderConfig(d *schema.ResourceData) providerConfig {
+	rateLimits := make(map[string]int)
+	for family, limit := range d.Get("rate_limits").(map[string]interface{}) {
+		rateLimits[family] = limit.(int)
+	}
+
+	maskedKeys := make([]string, 0)
+	for _, key := range d.Get("log_masked_keys").([]interface{}) {
+		maskedKeys = append(maskedKeys, key.(string))
+	}
+
+	return providerConfig{
+		username:           d.Get("username").(string),
+		password:           d.Get("password").(string),
+		url:                d.Get("url").(string),
+		maxRetries:         d.Get("max_retries").(int),
+		retryWaitMin:       d.Get("retry_wait_min").(string),
+		retryWaitMax:       d.Get("retry_wait_max").(string),
+		rateLimits:         rateLimits,
+		logMaskedKeys:      maskedKeys,
+		requestTimeout:     d.Get("request_timeout").(string),
+		httpProxy:          d.Get("http_proxy").(string),
+		caCertFile:         d.Get("ca_cert_file").(string),
+		caCertPEM:          d.Get("ca_cert_pem").(string),
+		insecureSkipVerify: d.Get("insecure_skip_verify").(bool),
 	}
 }
 
-func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	username := d.Get("username").(string)
-	password := d.Get("password").(string)
-	url := d.Get("url").(string)
+func (config providerConfig) newClient(ctx context.Context, userAgent string) (*robot.Client, error) {
+	// durations are checked by validateDuration already
+	requestTimeout, _ := time.ParseDuration(config.requestTimeout)
+	retryWaitMin, _ := time.ParseDuration(config.retryWaitMin)
+	retryWaitMax, _ := time.ParseDuration(config.retryWaitMax)
+	if retryWaitMax < retryWaitMin {
+		return nil, fmt.Errorf("retry_wait_max (%s) must not be lower than retry_wait_min (%s)", retryWaitMax, retryWaitMin)
+	}
 
-	var diags diag.Diagnostics
+	httpClient, err := newHTTPClient(transportConfig{
+		timeout:            requestTimeout,
+		proxyURL:           config.httpProxy,
+		caCertFile:         config.caCertFile,
+		caCertPEM:          config.caCertPEM,
+		insecureSkipVerify: config.insecureSkipVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return NewHetznerRobotClient(username, password, url), diags
+	// HETZNERROBOT_RECORD=1 captures the traffic of a run into a sanitized cassette,
+	// which tests replay later on (see recorder).
+	if recorder.ModeFromEnv() == recorder.ModeRecord {
+		cassette := os.Getenv(recorder.EnvRecordFile)
+		if cassette == "" {
+			cassette = recorder.DefaultCassettePath
+		}
+		rec, err := recorder.New(cassette, recorder.ModeRecord, httpClient.Transport, recorder.Options{
+			AnonymizeIPs: os.Getenv(recorder.EnvRecordAnonymize) == "1",
+		})
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = rec
+		tflog.Info(ctx, "recording Hetzner webservice traffic", map[string]interface{}{"cassette": cassette})
+	}
+
+	return robot.NewClient(config.username, config.password,
+		robot.WithBaseURL(config.url),
+		robot.WithUserAgent(userAgent),
+		robot.WithHTTPClient(httpClient),
+		robot.WithRetry(config.maxRetries, retryWaitMin, retryWaitMax),
+		robot.WithRateLimits(config.rateLimits),
+		robot.WithMaskedKeys(config.logMaskedKeys...),
+	), nil
+}
+
+// clientCache hands the SDK and the framework half of the muxed provider the same
+// client for the same configuration, so both share one rate limiter.
+type clientCache struct {
+	mu      sync.Mutex
+	clients map[string]*robot.Client
+}
+
+func newClientCache() *clientCache {
+	return &clientCache{
+		clients: make(map[string]*robot.Client),
+	}
+}
+
+func (c *clientCache) client(ctx context.Context, config providerConfig, userAgent string) (*robot.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := config.cacheKey()
+	if client, ok := c.clients[key]; ok {
+		return client, nil
+	}
+
+	client, err := config.newClient(ctx, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	c.clients[key] = client
+	return client, nil
+}
+
+// cacheKey identifies equal configurations. The SDK reads unset lists and maps as
+// empty, the framework as nil, and fmt prints maps sorted by key.
+func (config providerConfig) cacheKey() string {
+	if len(config.rateLimits) == 0 {
+		config.rateLimits = nil
+	}
+	if len(config.logMaskedKeys) == 0 {
+		config.logMaskedKeys = nil
+	}
+	return fmt.Sprintf("%#v", config)
+}
+
+func validateDuration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	duration, err := time.ParseDuration(v)
+	if err != nil {
+		return nil, []error{fmt.Errorf("expected %s to be a valid duration, got %q: %v", k, v, err)}
+	}
+	if duration < 0 {
+		return nil, []error{fmt.Errorf("expected %s to be a positive duration, got %q", k, v)}
+	}
+	return nil, nil
 }
@@ -0,0 +1,254 @@
+package hetznerrobot
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-mux/tf5to6server"
+	"github.com/hashicorp/terraform-plugin-mux/tf6muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/meta"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+// NewMuxServer combines the SDK provider, serving the data sources, and the
+// framework provider, serving the resources, into a single provider server. It speaks
+// protocol 6, which nested attributes require; the SDK provider only speaks protocol 5
+// and is upgraded.
+func NewMuxServer(ctx context.Context, version string) (tfprotov6.ProviderServer, error) {
+	clients := newClientCache()
+
+	sdkProvider := Provider()
+	sdkProvider.ConfigureContextFunc = providerConfigure(sdkProvider, version, clients)
+	sdkServer, err := tf5to6server.UpgradeServer(ctx, func() tfprotov5.ProviderServer {
+		return sdkProvider.GRPCProvider()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	muxServer, err := tf6muxserver.NewMuxServer(ctx,
+		func() tfprotov6.ProviderServer { return sdkServer },
+		providerserver.NewProtocol6(newFrameworkProvider(version, clients)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return muxServer.ProviderServer(), nil
+}
+
+type frameworkProvider struct {
+	version string
+	clients *clientCache
+}
+
+type frameworkProviderModel struct {
+	Username           types.String `tfsdk:"username"`
+	Password           types.String `tfsdk:"password"`
+	URL                types.String `tfsdk:"url"`
+	MaxRetries         types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin       types.String `tfsdk:"retry_wait_min"`
+	RetryWaitMax       types.String `tfsdk:"retry_wait_max"`
+	RateLimits         types.Map    `tfsdk:"rate_limits"`
+	LogMaskedKeys      types.List   `tfsdk:"log_masked_keys"`
+	RequestTimeout     types.String `tfsdk:"request_timeout"`
+	HTTPProxy          types.String `tfsdk:"http_proxy"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+}
+
+func newFrameworkProvider(version string, clients *clientCache) provider.Provider {
+	return &frameworkProvider{
+		version: version,
+		clients: clients,
+	}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "hetzner-robot"
+	resp.Version = p.version
+}
+
+// Schema mirrors the schema of the SDK provider, the mux server requires both to be
+// identical. Defaults and validation are left to the SDK provider.
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	sdkSchema := Provider().Schema
+
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"username": schema.StringAttribute{
+				Optional: true,
+			},
+			"password": schema.StringAttribute{
+				Optional: true,
+			},
+			"url": schema.StringAttribute{
+				Optional: true,
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: sdkSchema["max_retries"].Description,
+			},
+			"retry_wait_min": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["retry_wait_min"].Description,
+			},
+			"retry_wait_max": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["retry_wait_max"].Description,
+			},
+			"rate_limits": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.Int64Type,
+				Description: sdkSchema["rate_limits"].Description,
+			},
+			"log_masked_keys": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: sdkSchema["log_masked_keys"].Description,
+			},
+			"request_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["request_timeout"].Description,
+			},
+			"http_proxy": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["http_proxy"].Description,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["ca_cert_file"].Description,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["ca_cert_pem"].Description,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional:    true,
+				Description: sdkSchema["insecure_skip_verify"].Description,
+			},
+		},
+	}
+}
+
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var model frameworkProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config := providerConfig{
+		username:           stringOrDefault(model.Username, os.Getenv("HETZNERROBOT_USERNAME")),
+		password:           stringOrDefault(model.Password, os.Getenv("HETZNERROBOT_PASSWORD")),
+		url:                stringOrDefault(model.URL, os.Getenv("HETZNERROBOT_URL")),
+		maxRetries:         robot.DefaultMaxRetries,
+		retryWaitMin:       stringOrDefault(model.RetryWaitMin, robot.DefaultRetryWaitMin.String()),
+		retryWaitMax:       stringOrDefault(model.RetryWaitMax, robot.DefaultRetryWaitMax.String()),
+		rateLimits:         make(map[string]int),
+		logMaskedKeys:      make([]string, 0),
+		requestTimeout:     stringOrDefault(model.RequestTimeout, robot.DefaultRequestTimeout.String()),
+		httpProxy:          model.HTTPProxy.ValueString(),
+		caCertFile:         model.CACertFile.ValueString(),
+		caCertPEM:          model.CACertPEM.ValueString(),
+		insecureSkipVerify: model.InsecureSkipVerify.ValueBool(),
+	}
+	if config.url == "" {
+		config.url = robot.DefaultBaseURL
+	}
+	if !model.MaxRetries.IsNull() {
+		config.maxRetries = int(model.MaxRetries.ValueInt64())
+	}
+
+	var rateLimits map[string]int64
+	resp.Diagnostics.Append(model.RateLimits.ElementsAs(ctx, &rateLimits, false)...)
+	for family, limit := range rateLimits {
+		config.rateLimits[family] = int(limit)
+	}
+	resp.Diagnostics.Append(model.LogMaskedKeys.ElementsAs(ctx, &config.logMaskedKeys, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userAgent := fmt.Sprintf("Terraform/%s (+https://www.terraform.io) Terraform-Plugin-SDK/%s terraform-provider-hetzner-robot/%s", req.TerraformVersion, meta.SDKVersionString(), p.version)
+	client, err := p.clients.client(ctx, config, userAgent)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to configure the Hetzner webservice client", err.Error())
+		return
+	}
+
+	resp.DataSourceData = client
+	resp.ResourceData = client
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newBootResource,
+		newFirewallResource,
+		newFirewallTemplateResource,
+		newSshKeyResource,
+		newVSwitchResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		newBootOptionsDataSource,
+		newFirewallDataSource,
+		newFirewallRulesetDataSource,
+		newFirewallTemplatesDataSource,
+	}
+}
+
+// stringOrDefault returns the configured value, or fallback for null and unknown values.
+func stringOrDefault(value types.String, fallback string) string {
+	if value.IsNull() || value.IsUnknown() {
+		return fallback
+	}
+	return value.ValueString()
+}
+
+// resourceClient returns the client handed to resources by Configure. ProviderData is
+// nil while Terraform validates configurations, before the provider is configured.
+func resourceClient(req resource.ConfigureRequest, resp *resource.ConfigureResponse) *robot.Client {
+	if req.ProviderData == nil {
+		return nil
+	}
+
+	client, ok := req.ProviderData.(*robot.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected resource configure type", fmt.Sprintf("expected *robot.Client, got %T", req.ProviderData))
+	}
+	return client
+}
+
+// dataSourceClient is resourceClient for data sources.
+func dataSourceClient(req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) *robot.Client {
+	if req.ProviderData == nil {
+		return nil
+	}
+
+	client, ok := req.ProviderData.(*robot.Client)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected data source configure type", fmt.Sprintf("expected *robot.Client, got %T", req.ProviderData))
+	}
+	return client
+}
+
+// optionalString maps the empty strings Robot reports for unset values to null, the
+// way the SDK did, so optional attributes left out of the configuration do not diff.
+func optionalString(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
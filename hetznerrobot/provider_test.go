@@ -0,0 +1,374 @@
+package hetznerrobot
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProviderConfigure(t *testing.T) {
+	fake := robotfake.New(t)
+
+	p := New("test")()
+	d := schema.TestResourceDataRaw(t, p.Schema, map[string]interface{}{
+		"username":       robotfake.Username,
+		"password":       robotfake.Password,
+		"url":            fake.URL,
+		"retry_wait_min": "10s",
+		"retry_wait_max": "1s",
+	})
+	if _, diags := p.ConfigureContextFunc(context.Background(), d); !diags.HasError() {
+		t.Fatal("expected retry_wait_max lower than retry_wait_min to fail")
+	}
+
+	d = schema.TestResourceDataRaw(t, p.Schema, map[string]interface{}{
+		"username":    robotfake.Username,
+		"password":    robotfake.Password,
+		"url":         fake.URL,
+		"rate_limits": map[string]interface{}{"firewall": 10},
+	})
+	meta, diags := p.ConfigureContextFunc(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	fake.AddServer(321, "192.0.2.1", "web")
+	if _, err := meta.(*robot.Client).GetServer(context.Background(), 321); err != nil {
+		t.Fatal(err)
+	}
+	if userAgent := fake.UserAgent(); !strings.Contains(userAgent, "terraform-provider-hetzner-robot/test") {
+		t.Errorf("unexpected User-Agent %q", userAgent)
+	}
+}
+
+func TestMuxServer(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := NewMuxServer(ctx, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the mux server refuses to serve differing provider schemas
+	res, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", res.Diagnostics[0])
+	}
+	for _, name := range []string{"hetzner-robot_boot", "hetzner-robot_firewall", "hetzner-robot_firewall_template", "hetzner-robot_ssh_key", "hetzner-robot_vswitch"} {
+		if _, ok := res.ResourceSchemas[name]; !ok {
+			t.Errorf("resource %s is not served", name)
+		}
+	}
+	for _, name := range []string{"hetzner-robot_boot", "hetzner-robot_boot_options", "hetzner-robot_firewall", "hetzner-robot_firewall_ruleset", "hetzner-robot_firewall_templates", "hetzner-robot_server", "hetzner-robot_ssh_key", "hetzner-robot_vswitch"} {
+		if _, ok := res.DataSourceSchemas[name]; !ok {
+			t.Errorf("data source %s is not served", name)
+		}
+	}
+}
+
+// TestProviderConfigureSharedClient makes sure the data sources of the SDK provider
+// and the resources of the framework provider share one client, and so one rate
+// limiter, for an empty provider block.
+func TestProviderConfigureSharedClient(t *testing.T) {
+	ctx := context.Background()
+	fake := robotfake.New(t)
+	t.Setenv("HETZNERROBOT_USERNAME", robotfake.Username)
+	t.Setenv("HETZNERROBOT_PASSWORD", robotfake.Password)
+	t.Setenv("HETZNERROBOT_URL", fake.URL)
+
+	clients := newClientCache()
+
+	sdkProvider := Provider()
+	d := schema.TestResourceDataRaw(t, sdkProvider.Schema, map[string]interface{}{})
+	sdkClient, diags := providerConfigure(sdkProvider, "test", clients)(ctx, d)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	frameworkProvider := newFrameworkProvider("test", clients)
+	var schemaResp provider.SchemaResponse
+	frameworkProvider.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	state := tfsdk.State{Schema: schemaResp.Schema}
+	if diags := state.Set(ctx, frameworkProviderModel{
+		RateLimits:    types.MapNull(types.Int64Type),
+		LogMaskedKeys: types.ListNull(types.StringType),
+	}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	var resp provider.ConfigureResponse
+	frameworkProvider.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected error: %v", resp.Diagnostics)
+	}
+
+	if resp.ResourceData != sdkClient {
+		t.Error("expected the SDK and the framework provider to share a client")
+	}
+	if len(clients.clients) != 1 {
+		t.Errorf("expected 1 cached client, got %d", len(clients.clients))
+	}
+}
+
+// TestMuxServerUpgradeResourceState makes sure state written by the SDK based
+// resources is still readable.
+func TestMuxServerUpgradeResourceState(t *testing.T) {
+	ctx := context.Background()
+
+	server, err := NewMuxServer(ctx, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	states := map[string]string{
+		"hetzner-robot_boot": `{"id":"321","server_number":321,"active_profile":"rescue","language":"","operating_system":"linux",` +
+			`"authorized_keys":null,"ipv4_address":"192.0.2.10","ipv6_network":"2001:db8::/64","password":"secret"}`,
+		"hetzner-robot_firewall": `{"id":"192.0.2.10","server_ip":"192.0.2.10","active":true,"whitelist_hos":true,` +
+			`"rule":[{"name":"Allow ssh","dst_ip":"","dst_port":"22","src_ip":"","src_port":"","protocol":"tcp","tcp_flags":"","action":"accept"}]}`,
+		"hetzner-robot_ssh_key": `{"id":"` + testSshKeyFingerprint + `","name":"deploy","data":"` + testSshKeyData + `",` +
+			`"fingerprint":"` + testSshKeyFingerprint + `","type":"ED25519","size":256,"created_at":"2021-12-31 23:59:59"}`,
+		"hetzner-robot_vswitch": `{"id":"4321","name":"private","vlan":4010,"is_cancelled":false,` +
+			`"servers":[{"server_number":321,"server_ip":"192.0.2.10","server_ipv6_net":"2001:db8::","status":"ready"}],` +
+			`"subnets":[{"ip":"10.0.0.0","mask":24,"gateway":"10.0.0.1"}],"cloud_networks":[]}`,
+	}
+	for name, state := range states {
+		res, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+			TypeName: name,
+			RawState: &tfprotov6.RawState{JSON: []byte(state)},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Diagnostics) > 0 {
+			t.Errorf("%s: unexpected diagnostics: %s: %s", name, res.Diagnostics[0].Summary, res.Diagnostics[0].Detail)
+		}
+	}
+}
+
+// testAccPreCheck skips tests driving the Terraform CLI when none is available.
+func testAccPreCheck(t *testing.T) {
+	t.Helper()
+
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("terraform CLI not found, set TF_ACC_TERRAFORM_PATH to run this test")
+	}
+}
+
+// testAccProtoV6ProviderFactories points the provider at fake through the environment.
+func testAccProtoV6ProviderFactories(t *testing.T, fake *robotfake.Server) map[string]func() (tfprotov6.ProviderServer, error) {
+	t.Setenv("HETZNERROBOT_USERNAME", robotfake.Username)
+	t.Setenv("HETZNERROBOT_PASSWORD", robotfake.Password)
+	t.Setenv("HETZNERROBOT_URL", fake.URL)
+
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"hetzner-robot": func() (tfprotov6.ProviderServer, error) {
+			return NewMuxServer(context.Background(), "test")
+		},
+	}
+}
+
+// testClient returns a client talking to fake, with retries fast enough for tests.
+func testClient(fake *robotfake.Server) *robot.Client {
+	return robot.NewClient(robotfake.Username, robotfake.Password,
+		robot.WithBaseURL(fake.URL),
+		robot.WithRetry(3, time.Millisecond, 10*time.Millisecond),
+	)
+}
+
+// testResource drives a framework resource against fake without Terraform.
+type testResource struct {
+	t        *testing.T
+	resource resource.Resource
+	empty    tfsdk.State
+}
+
+func newTestResource(t *testing.T, r resource.Resource, fake *robotfake.Server) *testResource {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("schema: %v", schemaResp.Diagnostics)
+	}
+
+	var configureResp resource.ConfigureResponse
+	r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: testClient(fake)}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("configure: %v", configureResp.Diagnostics)
+	}
+
+	return &testResource{
+		t:        t,
+		resource: r,
+		empty: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+}
+
+// state returns a state holding model, which has to be the resource model.
+func (r *testResource) state(model interface{}) tfsdk.State {
+	r.t.Helper()
+
+	state := r.empty
+	if diags := state.Set(context.Background(), model); diags.HasError() {
+		r.t.Fatalf("unable to set state: %v", diags)
+	}
+	return state
+}
+
+func (r *testResource) plan(model interface{}) tfsdk.Plan {
+	state := r.state(model)
+	return tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}
+}
+
+func (r *testResource) validate(model interface{}) diag.Diagnostics {
+	state := r.state(model)
+	resp := resource.ValidateConfigResponse{}
+	r.resource.(resource.ResourceWithValidateConfig).ValidateConfig(context.Background(),
+		resource.ValidateConfigRequest{Config: tfsdk.Config{Schema: state.Schema, Raw: state.Raw}}, &resp)
+	return resp.Diagnostics
+}
+
+func (r *testResource) create(model interface{}) (tfsdk.State, diag.Diagnostics) {
+	resp := resource.CreateResponse{State: r.empty}
+	r.resource.Create(context.Background(), resource.CreateRequest{Plan: r.plan(model)}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+func (r *testResource) read(state tfsdk.State) (tfsdk.State, diag.Diagnostics) {
+	resp := resource.ReadResponse{State: state}
+	r.resource.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+func (r *testResource) update(state tfsdk.State, model interface{}) (tfsdk.State, diag.Diagnostics) {
+	resp := resource.UpdateResponse{State: state}
+	r.resource.Update(context.Background(), resource.UpdateRequest{State: state, Plan: r.plan(model)}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+// modifyPlan plans model, used as config as well, against state, which is r.empty
+// for a new resource.
+func (r *testResource) modifyPlan(state tfsdk.State, model interface{}) (tfsdk.Plan, diag.Diagnostics) {
+	plan := r.plan(model)
+	resp := resource.ModifyPlanResponse{Plan: plan}
+	r.resource.(resource.ResourceWithModifyPlan).ModifyPlan(context.Background(), resource.ModifyPlanRequest{
+		Config: tfsdk.Config{Schema: plan.Schema, Raw: plan.Raw},
+		State:  state,
+		Plan:   plan,
+	}, &resp)
+	return resp.Plan, resp.Diagnostics
+}
+
+func (r *testResource) delete(state tfsdk.State) diag.Diagnostics {
+	resp := resource.DeleteResponse{State: state}
+	r.resource.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+	return resp.Diagnostics
+}
+
+func (r *testResource) importState(id string) (tfsdk.State, diag.Diagnostics) {
+	resp := resource.ImportStateResponse{State: r.empty}
+	r.resource.(resource.ResourceWithImportState).ImportState(context.Background(), resource.ImportStateRequest{ID: id}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+// get reads state into model, failing the test when the resource was removed.
+func (r *testResource) get(state tfsdk.State, model interface{}) {
+	r.t.Helper()
+
+	if state.Raw.IsNull() {
+		r.t.Fatal("resource was removed from state")
+	}
+	if diags := state.Get(context.Background(), model); diags.HasError() {
+		r.t.Fatalf("unable to get state: %v", diags)
+	}
+}
+
+// testDataSource drives a framework data source against fake without Terraform. fake
+// may be nil for data sources which don't talk to Robot.
+type testDataSource struct {
+	t          *testing.T
+	dataSource datasource.DataSource
+	empty      tfsdk.State
+}
+
+func newTestDataSource(t *testing.T, d datasource.DataSource, fake *robotfake.Server) *testDataSource {
+	t.Helper()
+	ctx := context.Background()
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("schema: %v", schemaResp.Diagnostics)
+	}
+
+	if fake != nil {
+		var configureResp datasource.ConfigureResponse
+		d.(datasource.DataSourceWithConfigure).Configure(ctx, datasource.ConfigureRequest{ProviderData: testClient(fake)}, &configureResp)
+		if configureResp.Diagnostics.HasError() {
+			t.Fatalf("configure: %v", configureResp.Diagnostics)
+		}
+	}
+
+	return &testDataSource{
+		t:          t,
+		dataSource: d,
+		empty: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+}
+
+// read reads the data source with config, which has to be the data source model.
+func (d *testDataSource) read(config interface{}) (tfsdk.State, diag.Diagnostics) {
+	d.t.Helper()
+
+	configState := d.empty
+	if diags := configState.Set(context.Background(), config); diags.HasError() {
+		d.t.Fatalf("unable to set config: %v", diags)
+	}
+	resp := datasource.ReadResponse{State: d.empty}
+	d.dataSource.Read(context.Background(), datasource.ReadRequest{Config: tfsdk.Config{Schema: configState.Schema, Raw: configState.Raw}}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+// get reads state into model.
+func (d *testDataSource) get(state tfsdk.State, model interface{}) {
+	d.t.Helper()
+
+	if diags := state.Get(context.Background(), model); diags.HasError() {
+		d.t.Fatalf("unable to get state: %v", diags)
+	}
+}
@@ -0,0 +1,228 @@
+// Package recorder implements an http.RoundTripper which records Robot webservice
+// traffic into sanitized cassettes and replays it in tests.
+//
+// Recording is enabled with HETZNERROBOT_RECORD=1. Credentials are never written,
+// passwords and the key data of the authorized and host keys of boot profiles are
+// masked and public IP addresses can optionally be replaced by documentation addresses
+// (RFC 5737 / RFC 3849).
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette and fails on unmatched ones.
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests and appends them to the cassette.
+	ModeRecord
+)
+
+const (
+	EnvRecord           = "HETZNERROBOT_RECORD"
+	EnvRecordFile       = "HETZNERROBOT_RECORD_FILE"
+	EnvRecordAnonymize  = "HETZNERROBOT_RECORD_ANONYMIZE_IPS"
+	DefaultCassettePath = "testdata/hetzner-robot-cassette.json"
+)
+
+// ModeFromEnv returns ModeRecord when HETZNERROBOT_RECORD=1.
+func ModeFromEnv() Mode {
+	if os.Getenv(EnvRecord) == "1" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+type Options struct {
+	// AnonymizeIPs replaces public IP addresses by documentation addresses.
+	AnonymizeIPs bool
+}
+
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string `json:"method"`
+	URI    string `json:"uri"`
+	Body   string `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"`
+}
+
+// Recorder records or replays the requests sent through it.
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+	sanitizer *sanitizer
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a recorder for the cassette at path. In replay mode the cassette has to
+// exist, in record mode it is (re)written after every request. transport is used to
+// reach the webservice when recording, http.DefaultTransport when nil.
+func New(path string, mode Mode, transport http.RoundTripper, options Options) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: transport,
+		sanitizer: newSanitizer(options.AnonymizeIPs),
+	}
+
+	if mode == ModeReplay {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(content, &r.cassette); err != nil {
+			return nil, fmt.Errorf("unable to parse cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := r.sanitizer.request(req, body)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	res, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  recorded,
+		Response: r.sanitizer.response(res, resBody),
+	})
+	return res, r.save()
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		r.used[i] = true
+
+		header := make(http.Header)
+		for key, value := range interaction.Response.Headers {
+			header.Set(key, value)
+		}
+		return &http.Response{
+			StatusCode:    interaction.Response.StatusCode,
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("recorder: no unused interaction in %s matches %s %s", r.path, recorded.Method, recorded.URI)
+}
+
+// Unused returns the recorded requests which were not replayed.
+func (r *Recorder) Unused() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Request
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i].Request)
+		}
+	}
+	return unused
+}
+
+func (r *Recorder) save() error {
+	content, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(content, '\n'), 0o600)
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func matches(recorded Request, actual Request) bool {
+	return recorded.Method == actual.Method &&
+		recorded.URI == actual.URI &&
+		normalizeForm(recorded.Body) == normalizeForm(actual.Body)
+}
+
+// normalizeForm brings form encoded bodies into a canonical order, as cassettes may
+// have been edited by hand.
+func normalizeForm(body string) string {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return body
+	}
+	return values.Encode()
+}
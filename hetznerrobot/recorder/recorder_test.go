@@ -0,0 +1,239 @@
+package recorder
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+const testKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGb0Jgk2mR7u3C7bD8l5J2L3n0JwYqP2b9lQm2rX8n0a test"
+
+func send(t *testing.T, client *http.Client, method string, uri string, data url.Values) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, uri, strings.NewReader(data.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.SetBasicAuth(robotfake.Username, robotfake.Password)
+
+	res, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "88.99.10.20", "web")
+	path := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	rec, err := New(path, ModeRecord, nil, Options{AnonymizeIPs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rec}
+
+	status, recordedServer := send(t, client, http.MethodGet, fake.URL+"/server/321", nil)
+	if status != http.StatusOK || !strings.Contains(recordedServer, "88.99.10.20") {
+		t.Fatalf("recording must not alter the live response, got %d %s", status, recordedServer)
+	}
+	status, _ = send(t, client, http.MethodPost, fake.URL+"/key", url.Values{"name": {"deploy"}, "data": {testKey}})
+	if status != http.StatusCreated {
+		t.Fatalf("unexpected status %d", status)
+	}
+	send(t, client, http.MethodGet, fake.URL+"/boot/321", nil)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{robotfake.Password, "88.99.10.20", "pw321", "Authorization"} {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, content)
+		}
+	}
+	// public keys are no secret, replaying them masked would change the key
+	if !strings.Contains(string(content), "AAAAC3NzaC1lZDI1NTE5") {
+		t.Errorf("expected the public key to be kept:\n%s", content)
+	}
+	if !strings.Contains(string(content), "192.0.2.") {
+		t.Errorf("expected server ip to be replaced by a documentation address:\n%s", content)
+	}
+
+	replay, err := New(path, ModeReplay, nil, Options{AnonymizeIPs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: replay}
+
+	// the host is not part of the recording, the fake is gone by now as far as the
+	// recorder is concerned
+	status, body := send(t, client, http.MethodGet, "https://robot.example.com/server/321", nil)
+	if status != http.StatusOK || !strings.Contains(body, `"server_number":321`) {
+		t.Fatalf("unexpected replayed response %d %s", status, body)
+	}
+	status, _ = send(t, client, http.MethodPost, "https://robot.example.com/key", url.Values{"data": {testKey}, "name": {"deploy"}})
+	if status != http.StatusCreated {
+		t.Fatalf("unexpected replayed status %d", status)
+	}
+
+	if unused := replay.Unused(); len(unused) != 1 || unused[0].URI != "/boot/321" {
+		t.Errorf("unexpected unused interactions: %v", unused)
+	}
+
+	// every interaction is only replayed once
+	req, _ := http.NewRequest(http.MethodGet, "https://robot.example.com/server/321", nil)
+	if _, err := client.Do(req); err == nil || !strings.Contains(err.Error(), "no unused interaction") {
+		t.Errorf("expected replaying an interaction twice to fail, got %v", err)
+	}
+}
+
+// TestReplayBootKeys makes sure the client parses the authorized and host keys of a
+// replayed boot profile, only their key data is masked.
+func TestReplayBootKeys(t *testing.T) {
+	ctx := context.Background()
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "web")
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := New(path, ModeRecord, nil, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := robot.NewClient(robotfake.Username, robotfake.Password, robot.WithBaseURL(fake.URL), robot.WithHTTPClient(&http.Client{Transport: rec}))
+	key, err := client.CreateSSHKey(ctx, "deploy", testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded, err := client.SetBootProfile(ctx, 321, "rescue", robot.BootProfileOptions{OperatingSystem: "linux", AuthorizedKeys: []string{key.Fingerprint}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := New(path, ModeReplay, nil, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = robot.NewClient(robotfake.Username, robotfake.Password, robot.WithBaseURL("https://robot.example.com"), robot.WithHTTPClient(&http.Client{Transport: replay}))
+	if _, err := client.CreateSSHKey(ctx, "deploy", testKey); err != nil {
+		t.Fatal(err)
+	}
+	replayed, err := client.SetBootProfile(ctx, 321, "rescue", robot.BootProfileOptions{OperatingSystem: "linux", AuthorizedKeys: []string{key.Fingerprint}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(replayed.AuthorizedKeys) != 1 || replayed.AuthorizedKeys[0] != recorded.AuthorizedKeys[0] {
+		t.Errorf("expected authorized keys %v, got %v", recorded.AuthorizedKeys, replayed.AuthorizedKeys)
+	}
+	if len(replayed.HostKeys) == 0 || len(replayed.HostKeys) != len(recorded.HostKeys) || replayed.HostKeys[0] != recorded.HostKeys[0] {
+		t.Errorf("expected host keys %v, got %v", recorded.HostKeys, replayed.HostKeys)
+	}
+	if replayed.Password != maskedValue {
+		t.Errorf("expected the password to be masked, got %q", replayed.Password)
+	}
+}
+
+func TestReplayUnmatched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	cassette := `{"interactions":[{"request":{"method":"GET","uri":"/key"},"response":{"status_code":200,"body":"[]"}}]}`
+	if err := os.WriteFile(path, []byte(cassette), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := New(path, ModeReplay, nil, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rec}
+
+	for _, req := range []*http.Request{
+		mustRequest(t, http.MethodPost, "https://robot.example.com/key", ""),
+		mustRequest(t, http.MethodGet, "https://robot.example.com/key?name=deploy", ""),
+		mustRequest(t, http.MethodGet, "https://robot.example.com/boot/1", ""),
+	} {
+		if _, err := client.Do(req); err == nil {
+			t.Errorf("expected %s %s not to match", req.Method, req.URL)
+		}
+	}
+
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil, Options{}); err == nil {
+		t.Error("expected a missing cassette to fail in replay mode")
+	}
+}
+
+func TestSanitizer(t *testing.T) {
+	s := newSanitizer(true)
+
+	form := s.form(url.Values{
+		"password":                {"hunter2"},
+		"authorized_key[]":        {"aa:bb"},
+		"rules[input][0][name]":   {"ssh"},
+		"rules[input][0][src_ip]": {"88.99.10.20/32"},
+		"rules[input][1][src_ip]": {"10.0.0.0/8"},
+		"rules[input][2][src_ip]": {"0.0.0.0/0"},
+		"rules[input][3][dst_ip]": {"2a01:4f8:1:2::/64"},
+	}.Encode())
+	values, _ := url.ParseQuery(form)
+
+	expected := map[string]string{
+		"password":                maskedValue,
+		"authorized_key[]":        "aa:bb",
+		"rules[input][0][name]":   "ssh",
+		"rules[input][0][src_ip]": "192.0.2.1/32",
+		"rules[input][1][src_ip]": "10.0.0.0/8",
+		"rules[input][2][src_ip]": "0.0.0.0/0",
+		"rules[input][3][dst_ip]": "2001:db8:1::/64",
+	}
+	for key, value := range expected {
+		if values.Get(key) != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, values.Get(key))
+		}
+	}
+
+	// addresses are replaced consistently across requests and responses
+	if body := s.json([]byte(`{"server":{"server_ip":"88.99.10.20","password":"hunter2"}}`)); body != `{"server":{"password":"***","server_ip":"192.0.2.1"}}` {
+		t.Errorf("unexpected sanitized body %s", body)
+	}
+
+	// key data is masked, the keys themselves are kept for the client to parse on replay
+	body := s.json([]byte(`{"rescue":{"host_key":[{"key":{"fingerprint":"aa:bb","type":"ED25519","size":256,"data":"ssh-ed25519 AAAA"}}]}}`))
+	if body != `{"rescue":{"host_key":[{"key":{"data":"***","fingerprint":"aa:bb","size":256,"type":"ED25519"}}]}}` {
+		t.Errorf("unexpected sanitized body %s", body)
+	}
+	if value := s.value("203.0.113.7"); value != "203.0.113.7" {
+		t.Errorf("expected documentation addresses to be kept, got %s", value)
+	}
+
+	if value := newSanitizer(false).value("88.99.10.20"); value != "88.99.10.20" {
+		t.Errorf("expected addresses to be kept without AnonymizeIPs, got %s", value)
+	}
+}
+
+func mustRequest(t *testing.T, method string, uri string, body string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(method, uri, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
@@ -0,0 +1,204 @@
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/redact"
+)
+
+const maskedValue = redact.Value
+
+// Keys whose values never end up in a cassette, in form bodies as well as JSON.
+var sensitiveKeys = redact.KeySet(redact.CassetteKeys)
+
+// Keys holding key material, whose "data" values never end up in a cassette.
+var keyMaterialKeys = redact.KeySet(redact.KeyMaterialKeys)
+
+// Documentation networks, used as replacements and therefore never replaced themselves.
+var documentationNetworks = []*net.IPNet{
+	mustParseCIDR("192.0.2.0/24"),
+	mustParseCIDR("198.51.100.0/24"),
+	mustParseCIDR("203.0.113.0/24"),
+	mustParseCIDR("2001:db8::/32"),
+}
+
+// Response headers worth keeping, everything else (cookies, request ids, ...) is dropped.
+var keptHeaders = []string{"Content-Type", "Retry-After"}
+
+type sanitizer struct {
+	anonymizeIPs bool
+
+	mu        sync.Mutex
+	ips       map[string]string
+	ipv4Count int
+	ipv6Count int
+}
+
+func newSanitizer(anonymizeIPs bool) *sanitizer {
+	return &sanitizer{
+		anonymizeIPs: anonymizeIPs,
+		ips:          make(map[string]string),
+	}
+}
+
+// request returns the recorded form of req. Headers, and with them the credentials,
+// are not recorded at all; the host is dropped so cassettes replay against any url.
+func (s *sanitizer) request(req *http.Request, body []byte) Request {
+	segments := strings.Split(req.URL.Path, "/")
+	for i, segment := range segments {
+		segments[i] = s.value(segment)
+	}
+	uri := strings.Join(segments, "/")
+	if req.URL.RawQuery != "" {
+		uri += "?" + s.form(req.URL.RawQuery)
+	}
+
+	return Request{
+		Method: req.Method,
+		URI:    uri,
+		Body:   s.form(string(body)),
+	}
+}
+
+func (s *sanitizer) response(res *http.Response, body []byte) Response {
+	headers := make(map[string]string)
+	for _, key := range keptHeaders {
+		if value := res.Header.Get(key); value != "" {
+			headers[key] = value
+		}
+	}
+
+	return Response{
+		StatusCode: res.StatusCode,
+		Headers:    headers,
+		Body:       s.json(body),
+	}
+}
+
+func (s *sanitizer) form(body string) string {
+	values, err := url.ParseQuery(body)
+	if err != nil || body == "" {
+		return body
+	}
+	// sorted, so addresses are numbered in a stable order
+	for _, key := range sortedKeys(values) {
+		entries := values[key]
+		for i, entry := range entries {
+			if isSensitive(key) {
+				entries[i] = maskedValue
+				continue
+			}
+			entries[i] = s.value(entry)
+		}
+	}
+	return values.Encode()
+}
+
+func (s *sanitizer) json(body []byte) string {
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return string(body)
+	}
+	sanitized, err := json.Marshal(s.jsonValue(document, false))
+	if err != nil {
+		return string(body)
+	}
+	return string(sanitized)
+}
+
+// jsonValue masks secret leaf values and keeps the shape of the document, which the
+// client parses on replay. inKeyMaterial is set below the authorized and host keys.
+func (s *sanitizer) jsonValue(value interface{}, inKeyMaterial bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := v[key]
+			if _, isString := child.(string); isString && (isSensitive(key) || inKeyMaterial && key == "data") {
+				v[key] = maskedValue
+				continue
+			}
+			v[key] = s.jsonValue(child, inKeyMaterial || keyMaterialKeys[key])
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = s.jsonValue(child, inKeyMaterial)
+		}
+	case string:
+		return s.value(v)
+	}
+	return value
+}
+
+// value anonymizes a single value when it is a public IP address or network.
+func (s *sanitizer) value(value string) string {
+	if !s.anonymizeIPs {
+		return value
+	}
+
+	address, mask, isNet := strings.Cut(value, "/")
+	ip := net.ParseIP(address)
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() || isDocumentationIP(ip) {
+		return value
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	anonymized, ok := s.ips[ip.String()]
+	if !ok {
+		if ip.To4() != nil {
+			s.ipv4Count++
+			anonymized = fmt.Sprintf("192.0.2.%d", (s.ipv4Count-1)%254+1)
+		} else {
+			s.ipv6Count++
+			anonymized = fmt.Sprintf("2001:db8:%x::", s.ipv6Count)
+		}
+		s.ips[ip.String()] = anonymized
+	}
+	if isNet {
+		return anonymized + "/" + mask
+	}
+	return anonymized
+}
+
+func isSensitive(key string) bool {
+	return redact.Masked(sensitiveKeys, key)
+}
+
+func isDocumentationIP(ip net.IP) bool {
+	for _, network := range documentationNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+func sortedKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
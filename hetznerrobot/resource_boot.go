@@ -2,188 +2,556 @@ package hetznerrobot
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
-func resourceBoot() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceBootCreate,
-		ReadContext:   resourceBootRead,
-		UpdateContext: resourceBootUpdate,
-		DeleteContext: resourceBootDelete,
+var bootAPIFields = apiFieldMap(map[string]string{
+	"arch":           "architecture",
+	"authorized_key": "authorized_keys",
+	"dist":           "operating_system",
+	"hostname":       "hostname",
+	"keyboard":       "keyboard",
+	"lang":           "language",
+	"os":             "operating_system",
+	"type":           "reset_after_activation",
+})
 
-		Importer: &schema.ResourceImporter{
-			StateContext: resourceBootImportState,
-		},
+// defaultBootTimeout covers arming a profile and, with wait_for_ssh, the boot of the
+// server.
+const defaultBootTimeout = 15 * time.Minute
+
+var bootKeyType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"fingerprint": types.StringType,
+	"type":        types.StringType,
+	"size":        types.Int64Type,
+}}
+
+type bootResource struct {
+	client *robot.Client
+}
+
+type bootResourceModel struct {
+	ID                   types.String   `tfsdk:"id"`
+	ServerNumber         types.Int64    `tfsdk:"server_number"`
+	ActiveProfile        types.String   `tfsdk:"active_profile"`
+	Language             types.String   `tfsdk:"language"`
+	Hostname             types.String   `tfsdk:"hostname"`
+	OperatingSystem      types.String   `tfsdk:"operating_system"`
+	Architecture         types.Int64    `tfsdk:"architecture"`
+	Keyboard             types.String   `tfsdk:"keyboard"`
+	AuthorizedKeys       types.List     `tfsdk:"authorized_keys"`
+	IPv4Address          types.String   `tfsdk:"ipv4_address"`
+	IPv6Network          types.String   `tfsdk:"ipv6_network"`
+	Password             types.String   `tfsdk:"password"`
+	AuthorizedKeyDetails types.List     `tfsdk:"authorized_key_details"`
+	HostKeys             types.List     `tfsdk:"host_keys"`
+	ResetAfterActivation types.String   `tfsdk:"reset_after_activation"`
+	WaitForSSH           types.Bool     `tfsdk:"wait_for_ssh"`
+	Timeouts             timeouts.Value `tfsdk:"timeouts"`
+}
+
+func newBootResource() resource.Resource {
+	return &bootResource{}
+}
+
+func (r *bootResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_boot"
+}
 
-		Schema: map[string]*schema.Schema{
-			"server_number": {
-				Type:        schema.TypeInt,
-				Required:    true,
-				Description: "Server ID",
+func (r *bootResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"server_number": schema.Int64Attribute{
+				Required:      true,
+				Description:   "Server ID",
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
 			},
 			// optional
-			"active_profile": {
-				Type:        schema.TypeString, // Enum should be better (linux/rescue/...)
+			"active_profile": schema.StringAttribute{
 				Optional:    true,
-				Description: "Active boot profile",
+				Description: "Active boot profile, one of linux, rescue, vnc, windows, plesk or cpanel",
+				Validators:  []validator.String{stringvalidator.OneOf(robot.BootProfiles...)},
 			},
-			"language": {
-				Type:        schema.TypeString, // Enum should be better (amd64/...)
+			"language": schema.StringAttribute{
 				Optional:    true,
 				Description: "Language",
 			},
-			"operating_system": {
-				Type:        schema.TypeString, // Enum should be better (ubuntu_20.04/...)
+			"hostname": schema.StringAttribute{
+				Optional:    true,
+				Description: "Hostname of a plesk or cpanel installation",
+			},
+			"operating_system": schema.StringAttribute{
 				Optional:    true,
 				Description: "Active Operating System / Distribution",
 			},
-			"authorized_keys": {
-				Type:        schema.TypeList,
+			// Robot picks a default when not set
+			"architecture": schema.Int64Attribute{
 				Optional:    true,
-				Description: "One or more SSH key fingerprints",
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
+				Computed:    true,
+				Description: "Architecture, 64 or 32 bit, of every profile but windows",
+				Validators:  []validator.Int64{int64validator.OneOf(64, 32)},
 			},
-			// read-only / computed
-			"ipv4_address": {
-				Type:        schema.TypeString,
+			"keyboard": schema.StringAttribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "Server main IPv4 address",
+				Description: "Keyboard layout of the rescue system",
+			},
+			"authorized_keys": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "One or more SSH key fingerprints, for the linux and rescue profiles",
 			},
-			"ipv6_network": {
-				Type:        schema.TypeString,
+			"reset_after_activation": schema.StringAttribute{
+				Optional:    true,
+				Description: "Reset the server after arming the profile so it boots it, one of hw, sw or power",
+				Validators:  []validator.String{stringvalidator.OneOf(robot.ResetTypes...)},
+			},
+			"wait_for_ssh": schema.BoolAttribute{
+				Optional:    true,
 				Computed:    true,
-				Description: "Server main IPv6 net address",
+				Default:     booldefault.StaticBool(false),
+				Description: "Wait after the reset until SSH answers with one of host_keys, for the linux and rescue profiles",
 			},
-			"password": {
-				Type:        schema.TypeString,
+			// read-only / computed
+			"ipv4_address": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Server main IPv4 address",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"ipv6_network": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Server main IPv6 net address",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			// changes with every activation, so it is unknown until applied
+			"password": schema.StringAttribute{
 				Computed:    true,
 				Description: "Current Rescue System root password / Linux installation password or null",
 				Sensitive:   true,
 			},
+			"authorized_key_details": schema.ListAttribute{
+				Computed:    true,
+				ElementType: bootKeyType,
+				Description: "Fingerprint, type and size of the authorized SSH keys",
+			},
+			// changes with every activation, to be pinned in known_hosts
+			"host_keys": schema.ListAttribute{
+				Computed:    true,
+				ElementType: bootKeyType,
+				Description: "Fingerprint, type and size of the host keys of the rescue system or installation",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
 		},
 	}
 }
 
-func resourceBootImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	c := meta.(HetznerRobotClient)
+func (r *bootResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = resourceClient(req, resp)
+}
 
-	serverNumber, err := strconv.Atoi(d.Id())
-	if err != nil {
-		return nil, err
+func (r *bootResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config bootResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.ActiveProfile.IsUnknown() {
+		return
+	}
+
+	profile := config.ActiveProfile.ValueString()
+	hostname := profile == "plesk" || profile == "cpanel"
+	switch {
+	case hostname && config.Hostname.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("hostname"), "Missing hostname",
+			fmt.Sprintf("the %s profile requires a hostname", profile))
+	case !hostname && !config.Hostname.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("hostname"), "Unsupported hostname",
+			"hostname is only supported by the plesk and cpanel profiles")
+	}
+	if profile != "linux" && profile != "rescue" && len(config.AuthorizedKeys.Elements()) > 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("authorized_keys"), "Unsupported authorized_keys",
+			"authorized_keys are only supported by the linux and rescue profiles")
+	}
+	if profile == "windows" && !config.Architecture.IsNull() && !config.Architecture.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("architecture"), "Unsupported architecture",
+			"the windows profile has no architecture")
+	}
+	if profile != "rescue" && !config.Keyboard.IsNull() && !config.Keyboard.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("keyboard"), "Unsupported keyboard",
+			"keyboard is only supported by the rescue profile")
+	}
+
+	switch {
+	case profile == "" && !config.ResetAfterActivation.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("reset_after_activation"), "Missing active_profile",
+			"reset_after_activation requires an active_profile to boot")
+	case config.WaitForSSH.ValueBool() && config.ResetAfterActivation.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("wait_for_ssh"), "Missing reset_after_activation",
+			"wait_for_ssh requires reset_after_activation, the server only boots the profile after a reset")
+	case config.WaitForSSH.ValueBool() && profile != "linux" && profile != "rescue":
+		resp.Diagnostics.AddAttributeError(path.Root("wait_for_ssh"), "Unsupported wait_for_ssh",
+			"wait_for_ssh is only supported by the linux and rescue profiles, which report host keys")
+	}
+}
+
+// ModifyPlan checks the planned options against the ones Robot offers for the server,
+// so typos in e.g. operating_system fail at plan instead of apply.
+func (r *bootResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan, state bootResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() || plan.ActiveProfile.IsNull() || plan.ActiveProfile.IsUnknown() || plan.ServerNumber.IsUnknown() {
+		return
+	}
+	// nothing to check without a change, which also saves a request per plan
+	if !req.State.Raw.IsNull() && plan.ActiveProfile.Equal(state.ActiveProfile) && plan.OperatingSystem.Equal(state.OperatingSystem) &&
+		plan.Language.Equal(state.Language) && plan.Architecture.Equal(state.Architecture) && plan.Keyboard.Equal(state.Keyboard) {
+		return
 	}
 
-	boot, err := c.getBoot(ctx, serverNumber)
+	serverNumber := int(plan.ServerNumber.ValueInt64())
+	options, err := r.client.GetBootOptions(ctx, serverNumber)
+	if errors.Is(err, robot.ErrNotFound) {
+		return
+	}
 	if err != nil {
-		return nil, err
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to read boot options of server %d", serverNumber), err.Error())
+		return
 	}
 
-	d.Set("active_profile", boot.ActiveProfile)
-	d.Set("ipv4_address", boot.ServerIPv4)
-	d.Set("ipv6_network", boot.ServerIPv6)
-	d.Set("language", boot.Language)
-	d.Set("operating_system", boot.OperatingSystem)
-	d.Set("password", boot.Password)
-	d.Set("server_number", serverNumber)
+	profile := plan.ActiveProfile.ValueString()
+	profileOptions, ok := options[profile]
+	if !ok {
+		resp.Diagnostics.AddAttributeError(path.Root("active_profile"), "Unavailable boot profile",
+			fmt.Sprintf("the %s profile is not offered for server %d", profile, serverNumber))
+		return
+	}
+	validateBootOption(&resp.Diagnostics, "operating_system", plan.OperatingSystem, profileOptions.OperatingSystems, profile, serverNumber)
+	validateBootOption(&resp.Diagnostics, "language", plan.Language, profileOptions.Languages, profile, serverNumber)
+	validateBootOption(&resp.Diagnostics, "keyboard", plan.Keyboard, profileOptions.Keyboards, profile, serverNumber)
 
-	results := make([]*schema.ResourceData, 1)
-	results[0] = d
-	return results, nil
+	architectures := make([]string, 0, len(profileOptions.Architectures))
+	for _, architecture := range profileOptions.Architectures {
+		architectures = append(architectures, strconv.Itoa(architecture))
+	}
+	architecture := types.StringNull()
+	if !plan.Architecture.IsNull() && !plan.Architecture.IsUnknown() {
+		architecture = types.StringValue(strconv.FormatInt(plan.Architecture.ValueInt64(), 10))
+	}
+	validateBootOption(&resp.Diagnostics, "architecture", architecture, architectures, profile, serverNumber)
 }
 
-func resourceBootCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
-
-	serverNumber := d.Get("server_number").(int)
-	activeBootProfile := d.Get("active_profile").(string)
-	os := d.Get("operating_system").(string)
-	lang := d.Get("language").(string)
-	authorizedKeys := make([]string, 0)
-	if input := d.Get("authorized_keys"); input != nil {
-		for _, key := range input.([]interface{}) {
-			authorizedKeys = append(authorizedKeys, key.(string))
-		}
+// validateBootOption reports a configured value which is not among the available ones.
+// Robot lists no options for the armed profile, which are therefore not checked.
+func validateBootOption(diags *diag.Diagnostics, attribute string, value types.String, available []string, profile string, serverNumber int) {
+	if value.IsNull() || value.IsUnknown() || len(available) == 0 || slices.Contains(available, value.ValueString()) {
+		return
 	}
+	diags.AddAttributeError(path.Root(attribute), fmt.Sprintf("Unavailable %s", attribute),
+		fmt.Sprintf("%q is not available for the %s profile of server %d, available are: %s",
+			value.ValueString(), profile, serverNumber, strings.Join(available, ", ")))
+}
 
-	bootProfile, err := c.setBootProfile(ctx, serverNumber, activeBootProfile, os, lang, authorizedKeys)
+func (r *bootResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	serverNumber, err := strconv.Atoi(req.ID)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Invalid server number", fmt.Sprintf("expected a server number, got %q", req.ID))
+		return
 	}
 
-	d.Set("ipv4_address", bootProfile.ServerIPv4)
-	d.Set("ipv6_network", bootProfile.ServerIPv6)
-	d.Set("password", bootProfile.Password)
-	d.SetId(strconv.Itoa(serverNumber))
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("server_number"), serverNumber)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("wait_for_ssh"), false)...)
+}
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+func (r *bootResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan bootResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	return diags
+	timeout, diags := plan.Timeouts.Create(ctx, defaultBootTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	r.setBootProfile(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ID = types.StringValue(strconv.FormatInt(plan.ServerNumber.ValueInt64(), 10))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceBootRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *bootResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state bootResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	serverNumber, err := strconv.Atoi(d.Id())
+	serverNumber, err := strconv.Atoi(state.ID.ValueString())
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Invalid boot ID", err.Error())
+		return
 	}
 
-	boot, err := c.getBoot(ctx, serverNumber)
+	boot, err := r.client.GetBoot(ctx, serverNumber)
+	if errors.Is(err, robot.ErrNotFound) {
+		resp.Diagnostics.Append(removeGoneResource(ctx, &resp.State, "Boot profile of server", state.ID.ValueString(), err)...)
+		return
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to read boot profile of server %d", serverNumber), err.Error())
+		return
 	}
 
-	d.Set("active_profile", boot.ActiveProfile)
-	d.Set("ipv4_address", boot.ServerIPv4)
-	d.Set("ipv6_network", boot.ServerIPv6)
-	d.Set("language", boot.Language)
-	d.Set("operating_system", boot.OperatingSystem)
-	d.Set("password", boot.Password)
+	// Robot disarms a profile once the server booted it, which the reset made happen
+	if boot.ActiveProfile == "" && !state.ActiveProfile.IsNull() && !state.ResetAfterActivation.IsNull() {
+		state.IPv4Address = types.StringValue(boot.ServerIPv4)
+		state.IPv6Network = types.StringValue(boot.ServerIPv6)
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	state.ActiveProfile = optionalString(boot.ActiveProfile)
+	state.Language = optionalString(boot.Language)
+	state.Hostname = optionalString(boot.Hostname)
+	state.OperatingSystem = optionalString(boot.OperatingSystem)
+	resp.Diagnostics.Append(state.setBootProfile(boot)...)
 
-	return diags
+	// the keys Robot reports, so keys removed from the account show up as drift
+	fingerprints := make([]attr.Value, 0, len(boot.AuthorizedKeys))
+	for _, key := range boot.AuthorizedKeys {
+		fingerprints = append(fingerprints, types.StringValue(key.Fingerprint))
+	}
+	state.AuthorizedKeys = types.ListNull(types.StringType)
+	if len(fingerprints) > 0 {
+		state.AuthorizedKeys = types.ListValueMust(types.StringType, fingerprints)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *bootResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state bootResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// changing only how the profile is booted neither arms it nor resets the server again
+	if !plan.bootProfileChanged(state) {
+		plan.IPv4Address, plan.IPv6Network, plan.Password = state.IPv4Address, state.IPv6Network, state.Password
+		plan.Architecture, plan.Keyboard = state.Architecture, state.Keyboard
+		plan.AuthorizedKeyDetails, plan.HostKeys = state.AuthorizedKeyDetails, state.HostKeys
+		resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+		return
+	}
+
+	timeout, diags := plan.Timeouts.Update(ctx, defaultBootTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	r.setBootProfile(ctx, &plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceBootUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *bootResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state bootResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	serverNumber := d.Get("server_number").(int)
-	activeBootProfile := d.Get("active_profile").(string)
-	os := d.Get("operating_system").(string)
-	lang := d.Get("language").(string)
-	authorizedKeys := make([]string, 0)
-	if input := d.Get("authorized_keys"); input != nil {
-		for _, key := range input.([]interface{}) {
-			authorizedKeys = append(authorizedKeys, key.(string))
+	// a profile left armed would be booted by the next unrelated reset, one armed
+	// outside Terraform since is left alone
+	if profile := state.ActiveProfile.ValueString(); profile != "" {
+		r.deactivateBootProfile(ctx, int(state.ServerNumber.ValueInt64()), profile, &resp.Diagnostics)
+	}
+}
+
+// deactivateBootProfile disarms the armed profile of a server, if any. A non-empty
+// profile is only disarmed when it is the armed one.
+func (r *bootResource) deactivateBootProfile(ctx context.Context, serverNumber int, profile string, diags *diag.Diagnostics) {
+	boot, err := r.client.GetBoot(ctx, serverNumber)
+	if errors.Is(err, robot.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Unable to read boot profile of server %d", serverNumber), err.Error())
+		return
+	}
+	if boot.ActiveProfile == "" {
+		return
+	}
+	if profile != "" && boot.ActiveProfile != profile {
+		tflog.Info(ctx, "boot profile armed outside Terraform, keeping it", map[string]interface{}{"server_number": serverNumber, "profile": boot.ActiveProfile})
+		return
+	}
+
+	err = r.client.DeactivateBootProfile(ctx, serverNumber, boot.ActiveProfile)
+	if err != nil && !errors.Is(err, robot.ErrNotFound) {
+		diags.AddError(fmt.Sprintf("Unable to deactivate boot profile %q of server %d", boot.ActiveProfile, serverNumber), err.Error())
+	}
+}
+
+// setBootProfile arms the planned profile and stores the computed attributes of the
+// response in plan. Robot keeps the options of an armed profile, so it is disarmed
+// first, even if it is the planned one.
+func (r *bootResource) setBootProfile(ctx context.Context, plan *bootResourceModel, diags *diag.Diagnostics) {
+	serverNumber := int(plan.ServerNumber.ValueInt64())
+	activeBootProfile := plan.ActiveProfile.ValueString()
+
+	r.deactivateBootProfile(ctx, serverNumber, "", diags)
+	if diags.HasError() {
+		return
+	}
+	if activeBootProfile == "" {
+		boot, err := r.client.GetBoot(ctx, serverNumber)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Unable to read boot profile of server %d", serverNumber), err.Error())
+			return
 		}
+		diags.Append(plan.setBootProfile(boot)...)
+		return
+	}
+
+	authorizedKeys := make([]string, 0)
+	diags.Append(plan.AuthorizedKeys.ElementsAs(ctx, &authorizedKeys, false)...)
+	if diags.HasError() {
+		return
 	}
 
-	bootProfile, err := c.setBootProfile(ctx, serverNumber, activeBootProfile, os, lang, authorizedKeys)
+	bootProfile, err := r.client.SetBootProfile(ctx, serverNumber, activeBootProfile, robot.BootProfileOptions{
+		OperatingSystem: plan.OperatingSystem.ValueString(),
+		Language:        plan.Language.ValueString(),
+		Hostname:        plan.Hostname.ValueString(),
+		Architecture:    int(plan.Architecture.ValueInt64()),
+		Keyboard:        plan.Keyboard.ValueString(),
+		AuthorizedKeys:  authorizedKeys,
+	})
 	if err != nil {
-		return diag.FromErr(err)
+		diags.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to set boot profile %q for server %d", activeBootProfile, serverNumber), err, bootAPIFields)...)
+		return
 	}
 
-	d.Set("ipv4_address", bootProfile.ServerIPv4)
-	d.Set("ipv6_network", bootProfile.ServerIPv6)
-	d.Set("password", bootProfile.Password)
+	diags.Append(plan.setBootProfile(bootProfile)...)
+	if diags.HasError() || plan.ResetAfterActivation.IsNull() {
+		return
+	}
+
+	resetType := plan.ResetAfterActivation.ValueString()
+	if err := r.client.ResetServer(ctx, serverNumber, resetType); err != nil {
+		diags.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to reset server %d", serverNumber), err, bootAPIFields)...)
+		return
+	}
+	if !plan.WaitForSSH.ValueBool() {
+		return
+	}
+	if err := waitForSSH(ctx, bootProfile.ServerIPv4, bootProfile.HostKeys); err != nil {
+		diags.AddError(fmt.Sprintf("Server %d did not boot the %s profile", serverNumber, activeBootProfile), err.Error())
+	}
+}
+
+// bootProfileChanged reports whether the profile armed according to state differs from
+// the one planned, for unknown computed attributes only if they are configured.
+func (m bootResourceModel) bootProfileChanged(state bootResourceModel) bool {
+	return !m.ActiveProfile.Equal(state.ActiveProfile) ||
+		!m.OperatingSystem.Equal(state.OperatingSystem) ||
+		!m.Language.Equal(state.Language) ||
+		!m.Hostname.Equal(state.Hostname) ||
+		!m.AuthorizedKeys.Equal(state.AuthorizedKeys) ||
+		(!m.Architecture.IsUnknown() && !m.Architecture.Equal(state.Architecture)) ||
+		(!m.Keyboard.IsUnknown() && !m.Keyboard.Equal(state.Keyboard))
+}
 
-	// Warning or errors can be collected in a slice type
+// setBootProfile stores the attributes Robot computes for the active profile in m.
+func (m *bootResourceModel) setBootProfile(boot *robot.BootProfile) diag.Diagnostics {
 	var diags diag.Diagnostics
 
+	m.IPv4Address = types.StringValue(boot.ServerIPv4)
+	m.IPv6Network = types.StringValue(boot.ServerIPv6)
+	m.Password = optionalString(boot.Password)
+	m.Architecture = types.Int64Null()
+	if boot.Architecture != 0 {
+		m.Architecture = types.Int64Value(int64(boot.Architecture))
+	}
+	m.Keyboard = optionalString(boot.Keyboard)
+
+	var d diag.Diagnostics
+	m.AuthorizedKeyDetails, d = flattenBootKeys(boot.AuthorizedKeys)
+	diags.Append(d...)
+	m.HostKeys, d = flattenBootKeys(boot.HostKeys)
+	diags.Append(d...)
+
 	return diags
 }
 
-func resourceBootDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
+func flattenBootKeys(keys []robot.BootKey) (types.List, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	return diags
+	values := make([]attr.Value, 0, len(keys))
+	for _, key := range keys {
+		value, d := types.ObjectValue(bootKeyType.AttrTypes, map[string]attr.Value{
+			"fingerprint": types.StringValue(key.Fingerprint),
+			"type":        types.StringValue(key.Type),
+			"size":        types.Int64Value(int64(key.Size)),
+		})
+		diags.Append(d...)
+		values = append(values, value)
+	}
+	list, d := types.ListValue(bootKeyType, values)
+	diags.Append(d...)
+
+	return list, diags
 }
@@ -0,0 +1,516 @@
+package hetznerrobot
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestAccResourceBoot_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t, fake),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "hetzner-robot_boot" "test" {
+  server_number    = 321
+  active_profile   = "rescue"
+  operating_system = "linux"
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "id", "321"),
+					resource.TestCheckResourceAttr("hetzner-robot_boot.test", "ipv4_address", "192.0.2.10"),
+					resource.TestCheckResourceAttrSet("hetzner-robot_boot.test", "password"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceBoot(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	r := newTestResource(t, newBootResource(), fake)
+
+	state, diags := r.create(bootResourceModel{
+		ServerNumber:         types.Int64Value(321),
+		ActiveProfile:        types.StringValue("rescue"),
+		OperatingSystem:      types.StringValue("linux"),
+		AuthorizedKeys:       types.ListNull(types.StringType),
+		AuthorizedKeyDetails: types.ListNull(bootKeyType),
+		HostKeys:             types.ListNull(bootKeyType),
+		Timeouts:             testBootTimeouts,
+	})
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	var model bootResourceModel
+	r.get(state, &model)
+	if model.ID.ValueString() != "321" || model.IPv4Address.ValueString() != "192.0.2.10" || model.Password.ValueString() == "" {
+		t.Errorf("unexpected state after create: id %s, ip %s", model.ID, model.IPv4Address)
+	}
+
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r.get(state, &model)
+	if model.ActiveProfile.ValueString() != "rescue" || model.OperatingSystem.ValueString() != "linux" {
+		t.Errorf("unexpected profile %s / %s", model.ActiveProfile, model.OperatingSystem)
+	}
+
+	// activating the profile again is not an error
+	if state, diags = r.update(state, model); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+
+	fake.CancelServer(321)
+	if state, diags = r.read(state); diags.HasError() || !state.Raw.IsNull() {
+		t.Errorf("expected the boot profile of a cancelled server to be removed from state, got %v", diags)
+	}
+}
+
+func TestResourceBootImport(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	r := newTestResource(t, newBootResource(), fake)
+
+	if _, diags := r.importState("web"); !diags.HasError() {
+		t.Error("expected a non numeric server number to be rejected")
+	}
+
+	state, diags := r.importState("321")
+	if diags.HasError() {
+		t.Fatalf("import: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	var model bootResourceModel
+	r.get(state, &model)
+	if model.ServerNumber.ValueInt64() != 321 || model.IPv4Address.ValueString() != "192.0.2.10" {
+		t.Errorf("unexpected state after import: %+v", model)
+	}
+}
+
+func TestResourceBootInvalidDistribution(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	r := newTestResource(t, newBootResource(), fake)
+
+	_, diags := r.create(bootResourceModel{
+		ServerNumber:         types.Int64Value(321),
+		ActiveProfile:        types.StringValue("linux"),
+		OperatingSystem:      types.StringValue("Debian-12"),
+		Language:             types.StringValue("en"),
+		AuthorizedKeys:       types.ListNull(types.StringType),
+		AuthorizedKeyDetails: types.ListNull(bootKeyType),
+		HostKeys:             types.ListNull(bootKeyType),
+		Timeouts:             testBootTimeouts,
+	})
+	if len(diags) != 1 || !diags[0].(diag.DiagnosticWithPath).Path().Equal(path.Root("operating_system")) {
+		t.Errorf("expected a diagnostic pointing at operating_system, got %v", diags)
+	}
+}
+
+func TestResourceBootProfileSwitch(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	client := testClient(fake)
+	r := newTestResource(t, newBootResource(), fake)
+
+	state, diags := r.create(bootResourceModel{
+		ServerNumber:         types.Int64Value(321),
+		ActiveProfile:        types.StringValue("rescue"),
+		OperatingSystem:      types.StringValue("linux"),
+		AuthorizedKeys:       types.ListNull(types.StringType),
+		AuthorizedKeyDetails: types.ListNull(bootKeyType),
+		HostKeys:             types.ListNull(bootKeyType),
+		Timeouts:             testBootTimeouts,
+	})
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	var model bootResourceModel
+	r.get(state, &model)
+	model.ActiveProfile = types.StringValue("linux")
+	model.OperatingSystem = types.StringValue("Debian 12 base")
+	model.Language = types.StringValue("en")
+	if state, diags = r.update(state, model); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	boot, err := client.GetBoot(context.Background(), 321)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if boot.ActiveProfile != "linux" || boot.OperatingSystem != "Debian 12 base" {
+		t.Errorf("expected rescue to be replaced by linux, got %+v", boot)
+	}
+
+	if diags = r.delete(state); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if boot, err = client.GetBoot(context.Background(), 321); err != nil || boot.ActiveProfile != "" {
+		t.Errorf("expected no armed profile after destroy, got %+v %v", boot, err)
+	}
+
+	// a profile armed outside Terraform in the meantime is kept
+	if _, diags = r.create(model); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if err := client.DeactivateBootProfile(context.Background(), 321, "linux"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SetBootProfile(context.Background(), 321, "rescue", robot.BootProfileOptions{OperatingSystem: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+	if diags = r.delete(state); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if boot, err = client.GetBoot(context.Background(), 321); err != nil || boot.ActiveProfile != "rescue" {
+		t.Errorf("expected the rescue profile armed outside Terraform to be kept, got %+v %v", boot, err)
+	}
+}
+
+func TestResourceBootInstallers(t *testing.T) {
+	for _, tc := range []bootResourceModel{
+		{ActiveProfile: types.StringValue("vnc"), OperatingSystem: types.StringValue("Ubuntu 24.04"), Language: types.StringValue("de")},
+		{ActiveProfile: types.StringValue("windows"), OperatingSystem: types.StringValue("datacenter"), Language: types.StringValue("en")},
+		{ActiveProfile: types.StringValue("plesk"), OperatingSystem: types.StringValue("Debian 12 Plesk"), Language: types.StringValue("en"), Hostname: types.StringValue("plesk.example.com")},
+		{ActiveProfile: types.StringValue("cpanel"), OperatingSystem: types.StringValue("AlmaLinux 9 cPanel"), Language: types.StringValue("en"), Hostname: types.StringValue("cpanel.example.com")},
+	} {
+		fake := robotfake.New(t)
+		fake.AddServer(321, "192.0.2.10", "boot")
+		r := newTestResource(t, newBootResource(), fake)
+
+		tc.ServerNumber = types.Int64Value(321)
+		tc.AuthorizedKeys = types.ListNull(types.StringType)
+		tc.AuthorizedKeyDetails = types.ListNull(bootKeyType)
+		tc.HostKeys = types.ListNull(bootKeyType)
+		tc.Timeouts = testBootTimeouts
+		if diags := r.validate(tc); diags.HasError() {
+			t.Fatalf("%s: validate: %v", tc.ActiveProfile, diags)
+		}
+		state, diags := r.create(tc)
+		if diags.HasError() {
+			t.Fatalf("%s: create: %v", tc.ActiveProfile, diags)
+		}
+		if state, diags = r.read(state); diags.HasError() {
+			t.Fatalf("%s: read: %v", tc.ActiveProfile, diags)
+		}
+
+		var model bootResourceModel
+		r.get(state, &model)
+		if !model.ActiveProfile.Equal(tc.ActiveProfile) || !model.OperatingSystem.Equal(tc.OperatingSystem) || !model.Language.Equal(tc.Language) || !model.Hostname.Equal(tc.Hostname) {
+			t.Errorf("%s: unexpected state after read: %+v", tc.ActiveProfile, model)
+		}
+	}
+}
+
+func TestResourceBootValidateConfig(t *testing.T) {
+	r := newTestResource(t, newBootResource(), robotfake.New(t))
+	keys := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("aa:bb")})
+
+	for _, tc := range []struct {
+		model bootResourceModel
+		want  path.Path
+	}{
+		{model: bootResourceModel{ActiveProfile: types.StringValue("linux"), AuthorizedKeys: keys}},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("plesk"), Hostname: types.StringValue("plesk.example.com")}},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("cpanel")}, want: path.Root("hostname")},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("vnc"), Hostname: types.StringValue("vnc.example.com")}, want: path.Root("hostname")},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("windows"), AuthorizedKeys: keys}, want: path.Root("authorized_keys")},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("rescue"), Architecture: types.Int64Value(32), Keyboard: types.StringValue("de")}},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("windows"), Architecture: types.Int64Value(64)}, want: path.Root("architecture")},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("linux"), Keyboard: types.StringValue("de")}, want: path.Root("keyboard")},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("rescue"), ResetAfterActivation: types.StringValue("hw"), WaitForSSH: types.BoolValue(true)}},
+		{model: bootResourceModel{ResetAfterActivation: types.StringValue("hw")}, want: path.Root("reset_after_activation")},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("rescue"), WaitForSSH: types.BoolValue(true)}, want: path.Root("wait_for_ssh")},
+		{model: bootResourceModel{ActiveProfile: types.StringValue("vnc"), ResetAfterActivation: types.StringValue("sw"), WaitForSSH: types.BoolValue(true)}, want: path.Root("wait_for_ssh")},
+	} {
+		if tc.model.AuthorizedKeys.IsNull() {
+			tc.model.AuthorizedKeys = types.ListNull(types.StringType)
+		}
+		tc.model.AuthorizedKeyDetails = types.ListNull(bootKeyType)
+		tc.model.HostKeys = types.ListNull(bootKeyType)
+		tc.model.Timeouts = testBootTimeouts
+		diags := r.validate(tc.model)
+		if len(tc.want.Steps()) == 0 {
+			if diags.HasError() {
+				t.Errorf("%s: unexpected error %v", tc.model.ActiveProfile, diags)
+			}
+			continue
+		}
+		if len(diags) != 1 || !diags[0].(diag.DiagnosticWithPath).Path().Equal(tc.want) {
+			t.Errorf("%s: expected an error at %s, got %v", tc.model.ActiveProfile, tc.want, diags)
+		}
+	}
+}
+
+func TestResourceBootRescueOptions(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	client := testClient(fake)
+	r := newTestResource(t, newBootResource(), fake)
+
+	key, err := client.CreateSSHKey(context.Background(), "deploy", testSshKeyData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := types.ListValueMust(types.StringType, []attr.Value{types.StringValue(key.Fingerprint)})
+
+	state, diags := r.create(bootResourceModel{
+		ServerNumber:         types.Int64Value(321),
+		ActiveProfile:        types.StringValue("rescue"),
+		OperatingSystem:      types.StringValue("linux"),
+		Architecture:         types.Int64Value(32),
+		Keyboard:             types.StringValue("de"),
+		AuthorizedKeys:       keys,
+		AuthorizedKeyDetails: types.ListUnknown(bootKeyType),
+		HostKeys:             types.ListUnknown(bootKeyType),
+		Timeouts:             testBootTimeouts,
+	})
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	var model bootResourceModel
+	r.get(state, &model)
+	if model.Architecture.ValueInt64() != 32 || model.Keyboard.ValueString() != "de" || !model.AuthorizedKeys.Equal(keys) {
+		t.Errorf("unexpected rescue options after read: %s / %s / %s", model.Architecture, model.Keyboard, model.AuthorizedKeys)
+	}
+
+	var details, hostKeys []struct {
+		Fingerprint string `tfsdk:"fingerprint"`
+		Type        string `tfsdk:"type"`
+		Size        int64  `tfsdk:"size"`
+	}
+	model.AuthorizedKeyDetails.ElementsAs(context.Background(), &details, false)
+	model.HostKeys.ElementsAs(context.Background(), &hostKeys, false)
+	if len(details) != 1 || details[0].Fingerprint != key.Fingerprint || details[0].Type != key.Type || details[0].Size != int64(key.Size) {
+		t.Errorf("unexpected authorized key details %+v", details)
+	}
+	if len(hostKeys) == 0 || hostKeys[0].Fingerprint == "" || hostKeys[0].Type == "" {
+		t.Errorf("expected the host keys of the rescue system, got %+v", hostKeys)
+	}
+
+	// a key removed from the activation outside Terraform shows up as drift
+	if err := client.DeactivateBootProfile(context.Background(), 321, "rescue"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SetBootProfile(context.Background(), 321, "rescue", robot.BootProfileOptions{OperatingSystem: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r.get(state, &model)
+	if !model.AuthorizedKeys.IsNull() || model.Architecture.ValueInt64() != 64 || model.Keyboard.ValueString() != "us" {
+		t.Errorf("expected Robot's values after re-activation, got %s / %s / %s", model.AuthorizedKeys, model.Architecture, model.Keyboard)
+	}
+}
+
+func TestResourceBootModifyPlan(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	r := newTestResource(t, newBootResource(), fake)
+
+	model := func(profile string, operatingSystem string, language string, keyboard string) bootResourceModel {
+		return bootResourceModel{
+			ServerNumber:         types.Int64Value(321),
+			ActiveProfile:        types.StringValue(profile),
+			OperatingSystem:      types.StringValue(operatingSystem),
+			Language:             optionalString(language),
+			Keyboard:             optionalString(keyboard),
+			AuthorizedKeys:       types.ListNull(types.StringType),
+			AuthorizedKeyDetails: types.ListUnknown(bootKeyType),
+			HostKeys:             types.ListUnknown(bootKeyType),
+			Timeouts:             testBootTimeouts,
+		}
+	}
+
+	for _, tc := range []struct {
+		model bootResourceModel
+		want  path.Path
+	}{
+		{model: model("linux", "Debian 12 base", "en", "")},
+		{model: model("rescue", "linux", "", "de")},
+		{model: model("linux", "Debian-12", "en", ""), want: path.Root("operating_system")},
+		{model: model("vnc", "Ubuntu 24.04", "es", ""), want: path.Root("language")},
+		{model: model("rescue", "linux", "", "dvorak"), want: path.Root("keyboard")},
+	} {
+		_, diags := r.modifyPlan(r.empty, tc.model)
+		if len(tc.want.Steps()) == 0 {
+			if diags.HasError() {
+				t.Errorf("%s: unexpected error %v", tc.model.OperatingSystem, diags)
+			}
+			continue
+		}
+		if len(diags) != 1 || !diags[0].(diag.DiagnosticWithPath).Path().Equal(tc.want) {
+			t.Errorf("%s: expected an error at %s, got %v", tc.model.OperatingSystem, tc.want, diags)
+		}
+	}
+
+	// an unchanged profile is not checked again
+	state, diags := r.create(model("linux", "Debian 12 base", "en", ""))
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	var planned bootResourceModel
+	r.get(state, &planned)
+	requests := fake.Requests("boot")
+	if _, diags = r.modifyPlan(state, planned); diags.HasError() || fake.Requests("boot") != requests {
+		t.Errorf("expected no request for an unchanged profile, got %d requests and %v", fake.Requests("boot")-requests, diags)
+	}
+}
+
+var testBootTimeouts = timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+	"create": types.StringType,
+	"update": types.StringType,
+})}
+
+// testRescueModel is the plan of a rescue activation of server 321 which resets the
+// server and waits for SSH.
+func testRescueModel() bootResourceModel {
+	return bootResourceModel{
+		ServerNumber:         types.Int64Value(321),
+		ActiveProfile:        types.StringValue("rescue"),
+		OperatingSystem:      types.StringValue("linux"),
+		AuthorizedKeys:       types.ListNull(types.StringType),
+		AuthorizedKeyDetails: types.ListUnknown(bootKeyType),
+		HostKeys:             types.ListUnknown(bootKeyType),
+		ResetAfterActivation: types.StringValue("hw"),
+		WaitForSSH:           types.BoolValue(true),
+		Timeouts:             testBootTimeouts,
+	}
+}
+
+// serveTestSSH starts the SSH stand-in of server 321, which has to use 127.0.0.1, and
+// polls it instead of port 22.
+func serveTestSSH(t *testing.T, fake *robotfake.Server) {
+	_, port, err := net.SplitHostPort(fake.ServeSSH(t, 321))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prevPort, prevInterval := sshPort, sshPollInterval
+	sshPort, sshPollInterval = port, time.Millisecond
+	t.Cleanup(func() { sshPort, sshPollInterval = prevPort, prevInterval })
+}
+
+func TestResourceBootResetAndWaitForSSH(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "127.0.0.1", "boot")
+	serveTestSSH(t, fake)
+	r := newTestResource(t, newBootResource(), fake)
+
+	state, diags := r.create(testRescueModel())
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if resets := fake.Resets(321); !slices.Equal(resets, []string{"hw"}) {
+		t.Errorf("expected a hardware reset, got %v", resets)
+	}
+
+	// Robot disarmed the booted profile, which is no drift
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	var model bootResourceModel
+	r.get(state, &model)
+	if model.ActiveProfile.ValueString() != "rescue" || model.Password.IsNull() || len(model.HostKeys.Elements()) != 1 {
+		t.Errorf("expected the booted profile to stay in state, got %+v", model)
+	}
+
+	// changing how the profile is booted does not reset the server again
+	model.ResetAfterActivation = types.StringValue("power")
+	model.WaitForSSH = types.BoolValue(false)
+	if _, diags = r.update(state, model); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	if resets := fake.Resets(321); len(resets) != 1 {
+		t.Errorf("expected no further reset, got %v", resets)
+	}
+}
+
+func TestResourceBootWaitForSSHTimeout(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "127.0.0.1", "boot")
+	serveTestSSH(t, fake)
+	// the rescue system comes up with a host key Robot does not report
+	fake.SetHostKey(321, "ED25519", 256, "00:11:22:33:44:55:66:77:88:99:aa:bb:cc:dd:ee:ff")
+	r := newTestResource(t, newBootResource(), fake)
+
+	model := testRescueModel()
+	model.Timeouts = timeouts.Value{Object: types.ObjectValueMust(testBootTimeouts.AttributeTypes(context.Background()), map[string]attr.Value{
+		"create": types.StringValue("200ms"),
+		"update": types.StringNull(),
+	})}
+	_, diags := r.create(model)
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "timed out waiting for SSH") || !strings.Contains(diags[0].Detail(), "is not one of the host keys") {
+		t.Errorf("expected a timeout naming the mismatching host key, got %v", diags)
+	}
+}
+
+func TestResourceBootChangeInPlace(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	client := testClient(fake)
+	r := newTestResource(t, newBootResource(), fake)
+
+	state, diags := r.create(bootResourceModel{
+		ServerNumber:         types.Int64Value(321),
+		ActiveProfile:        types.StringValue("rescue"),
+		OperatingSystem:      types.StringValue("linux"),
+		AuthorizedKeys:       types.ListNull(types.StringType),
+		AuthorizedKeyDetails: types.ListNull(bootKeyType),
+		HostKeys:             types.ListNull(bootKeyType),
+		Timeouts:             testBootTimeouts,
+	})
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	var model bootResourceModel
+	r.get(state, &model)
+	model.OperatingSystem = types.StringValue("vkvm")
+	model.Password = types.StringUnknown()
+	if state, diags = r.update(state, model); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	boot, err := client.GetBoot(context.Background(), 321)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if boot.ActiveProfile != "rescue" || boot.OperatingSystem != "vkvm" {
+		t.Errorf("expected rescue to be re-armed with vkvm, got %s / %s", boot.ActiveProfile, boot.OperatingSystem)
+	}
+
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r.get(state, &model)
+	if model.OperatingSystem.ValueString() != "vkvm" {
+		t.Errorf("expected no drift after the change, got %s", model.OperatingSystem)
+	}
+}
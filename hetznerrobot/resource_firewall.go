@@ -2,249 +2,818 @@ package hetznerrobot
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+const (
+	defaultFirewallTimeout  = 10 * time.Minute
+	firewallStatusInProcess = "in process"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	firewallOnDestroyClear   = "clear"
+	firewallOnDestroyDisable = "disable"
+	firewallOnDestroyKeep    = "keep"
 )
 
-func resourceFirewall() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceFirewallCreate,
-		ReadContext:   resourceFirewallRead,
-		UpdateContext: resourceFirewallUpdate,
-		DeleteContext: resourceFirewallDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: resourceFirewallImportState,
-		},
-		Schema: map[string]*schema.Schema{
-			"server_ip": {
-				Type:     schema.TypeString,
-				Required: true,
+// firewallPollInterval is the wait between two status checks of a firewall which is
+// in process.
+var firewallPollInterval = 5 * time.Second
+
+var firewallAPIFields = firewallFieldMap(map[string]string{
+	"filter_ipv6":   "filter_ipv6",
+	"status":        "active",
+	"template_id":   "template_id",
+	"whitelist_hos": "whitelist_hos",
+})
+
+type firewallResource struct {
+	client *robot.Client
+}
+
+type firewallResourceModel struct {
+	ID              types.String                      `tfsdk:"id"`
+	ServerIP        types.String                      `tfsdk:"server_ip"`
+	ServerNumber    types.Int64                       `tfsdk:"server_number"`
+	Active          types.Bool                        `tfsdk:"active"`
+	WhitelistHOS    types.Bool                        `tfsdk:"whitelist_hos"`
+	FilterIPv6      types.Bool                        `tfsdk:"filter_ipv6"`
+	TemplateID      types.Int64                       `tfsdk:"template_id"`
+	Rule            []firewallRuleModel               `tfsdk:"rule"`
+	OutputRule      []firewallRuleModel               `tfsdk:"output_rule"`
+	NamedRule       map[string]firewallNamedRuleModel `tfsdk:"named_rule"`
+	NamedOutputRule map[string]firewallNamedRuleModel `tfsdk:"named_output_rule"`
+	OnDestroy       types.String                      `tfsdk:"on_destroy"`
+	Timeouts        timeouts.Value                    `tfsdk:"timeouts"`
+}
+
+type firewallRuleModel struct {
+	IPVersion types.String `tfsdk:"ip_version"`
+	Name      types.String `tfsdk:"name"`
+	DstIP     types.String `tfsdk:"dst_ip"`
+	DstPort   types.String `tfsdk:"dst_port"`
+	SrcIP     types.String `tfsdk:"src_ip"`
+	SrcPort   types.String `tfsdk:"src_port"`
+	Protocol  types.String `tfsdk:"protocol"`
+	TCPFlags  types.String `tfsdk:"tcp_flags"`
+	Action    types.String `tfsdk:"action"`
+}
+
+// firewallNamedRuleModel is a rule of named_rule or named_output_rule, named by its
+// key.
+type firewallNamedRuleModel struct {
+	Priority  types.Int64  `tfsdk:"priority"`
+	IPVersion types.String `tfsdk:"ip_version"`
+	DstIP     types.String `tfsdk:"dst_ip"`
+	DstPort   types.String `tfsdk:"dst_port"`
+	SrcIP     types.String `tfsdk:"src_ip"`
+	SrcPort   types.String `tfsdk:"src_port"`
+	Protocol  types.String `tfsdk:"protocol"`
+	TCPFlags  types.String `tfsdk:"tcp_flags"`
+	Action    types.String `tfsdk:"action"`
+}
+
+// rule returns the named rule as a rule of the chain.
+func (m firewallNamedRuleModel) rule(name string) firewallRuleModel {
+	return firewallRuleModel{
+		IPVersion: m.IPVersion,
+		Name:      types.StringValue(name),
+		DstIP:     m.DstIP,
+		DstPort:   m.DstPort,
+		SrcIP:     m.SrcIP,
+		SrcPort:   m.SrcPort,
+		Protocol:  m.Protocol,
+		TCPFlags:  m.TCPFlags,
+		Action:    m.Action,
+	}
+}
+
+func newFirewallResource() resource.Resource {
+	return &firewallResource{}
+}
+
+func (r *firewallResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall"
+}
+
+func (r *firewallResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"active": {
-				Type:     schema.TypeBool,
-				Required: true,
+			"server_ip": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Main IP of the server, exactly one of server_ip and server_number is required",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"whitelist_hos": {
-				Type:     schema.TypeBool,
-				Required: true,
+			"server_number": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Number of the server, exactly one of server_ip and server_number is required",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplaceIfConfigured(),
+					int64planmodifier.UseStateForUnknown(),
+				},
 			},
-			"rule": {
-				Type:     schema.TypeList,
+			"active": schema.BoolAttribute{
 				Required: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"dst_ip": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"dst_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"src_ip": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"src_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"protocol": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"tcp_flags": {
-							Type:     schema.TypeString,
-							Optional: true,
-						},
-						"action": {
-							Type: schema.TypeString,
-							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
-								"accept",
-								"discard",
-							}, false)),
-							Required: true,
-						},
-					},
+			},
+			"whitelist_hos": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Allow Hetzner services, required unless template_id is set",
+			},
+			"filter_ipv6": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Filter IPv6 traffic, when false all IPv6 traffic passes the firewall",
+			},
+			"template_id": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Firewall template to apply instead of rule / output_rule blocks",
+			},
+			"named_rule":        firewallNamedRuleAttribute("Incoming traffic rules keyed by name, instead of rule blocks"),
+			"named_output_rule": firewallNamedRuleAttribute("Outgoing traffic rules keyed by name, instead of output_rule blocks"),
+			"on_destroy": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(firewallOnDestroyClear),
+				Description: "What destroy does to the firewall: clear removes all rules, disable keeps the rules but deactivates the firewall, keep leaves it as it is",
+				Validators: []validator.String{
+					stringvalidator.OneOf(firewallOnDestroyClear, firewallOnDestroyDisable, firewallOnDestroyKeep),
 				},
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"rule":        firewallRuleBlock("Incoming traffic rules, at least one is required unless template_id is set"),
+			"output_rule": firewallRuleBlock("Outgoing traffic rules"),
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
 	}
 }
 
-func resourceFirewallImportState(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	c := m.(HetznerRobotClient)
+// firewallRuleType is the object type of a firewall rule in computed attributes.
+var firewallRuleType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"ip_version": types.StringType,
+	"name":       types.StringType,
+	"dst_ip":     types.StringType,
+	"dst_port":   types.StringType,
+	"src_ip":     types.StringType,
+	"src_port":   types.StringType,
+	"protocol":   types.StringType,
+	"tcp_flags":  types.StringType,
+	"action":     types.StringType,
+}}
+
+// firewallRuleBlock is the schema of a firewall rule chain.
+func firewallRuleBlock(description string) schema.ListNestedBlock {
+	attributes := firewallRuleAttributes()
+	attributes["ip_version"] = firewallRuleIPVersionAttribute()
+
+	return schema.ListNestedBlock{
+		Description:  description,
+		NestedObject: schema.NestedBlockObject{Attributes: attributes},
+	}
+}
 
-	firewallID := d.Id()
+// firewallNamedRuleAttribute is the schema of a firewall rule chain keyed by rule name,
+// so plans show added, changed and removed rules instead of shifted list entries.
+func firewallNamedRuleAttribute(description string) schema.MapNestedAttribute {
+	attributes := firewallRuleAttributes()
+	delete(attributes, "name")
+	attributes["priority"] = schema.Int64Attribute{
+		Required:    true,
+		Description: "Unique position of the rule, rules are evaluated by ascending priority",
+	}
+	attributes["ip_version"] = firewallRuleIPVersionAttribute()
 
-	firewall, err := c.getFirewall(ctx, firewallID)
-	if err != nil {
-		return nil, fmt.Errorf("could not find firewall with ID %s: %s", firewallID, err)
+	return schema.MapNestedAttribute{
+		Optional:     true,
+		Description:  description,
+		NestedObject: schema.NestedAttributeObject{Attributes: attributes},
 	}
+}
 
-	active := false
-	if firewall.Status == "active" {
-		active = true
+// firewallRuleIPVersionAttribute plans an unset ip_version as the family of the rule's
+// addresses.
+func firewallRuleIPVersionAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:      true,
+		Computed:      true,
+		Description:   "ipv4 or ipv6, defaults to the family of src_ip / dst_ip and to both without addresses",
+		Validators:    []validator.String{stringvalidator.OneOf("ipv4", "ipv6")},
+		PlanModifiers: []planmodifier.String{firewallRuleIPVersion{}},
 	}
+}
 
-	rules := make([]map[string]interface{}, 0)
-	for _, rule := range firewall.Rules.Input {
-		r := map[string]interface{}{
-			"name":      rule.Name,
-			"src_ip":    rule.SrcIP,
-			"src_port":  rule.SrcPort,
-			"dst_ip":    rule.DstIP,
-			"dst_port":  rule.DstPort,
-			"protocol":  rule.Protocol,
-			"tcp_flags": rule.TCPFlags,
-			"action":    rule.Action,
-		}
-		rules = append(rules, r)
+// firewallRuleAttributes are the attributes shared by all kinds of rule blocks.
+func firewallRuleAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Optional: true,
+		},
+		"dst_ip": schema.StringAttribute{
+			Optional: true,
+		},
+		"dst_port": schema.StringAttribute{
+			Optional: true,
+		},
+		"src_ip": schema.StringAttribute{
+			Optional: true,
+		},
+		"src_port": schema.StringAttribute{
+			Optional: true,
+		},
+		"protocol": schema.StringAttribute{
+			Optional: true,
+		},
+		"tcp_flags": schema.StringAttribute{
+			Optional: true,
+		},
+		"action": schema.StringAttribute{
+			Required:   true,
+			Validators: []validator.String{stringvalidator.OneOf("accept", "discard")},
+		},
+	}
+}
+
+func (r *firewallResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = resourceClient(req, resp)
+}
+
+func (r *firewallResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config struct {
+		ServerIP        types.String `tfsdk:"server_ip"`
+		ServerNumber    types.Int64  `tfsdk:"server_number"`
+		TemplateID      types.Int64  `tfsdk:"template_id"`
+		WhitelistHOS    types.Bool   `tfsdk:"whitelist_hos"`
+		FilterIPv6      types.Bool   `tfsdk:"filter_ipv6"`
+		Rule            types.List   `tfsdk:"rule"`
+		OutputRule      types.List   `tfsdk:"output_rule"`
+		NamedRule       types.Map    `tfsdk:"named_rule"`
+		NamedOutputRule types.Map    `tfsdk:"named_output_rule"`
+	}
+	for name, target := range map[string]interface{}{
+		"server_ip":         &config.ServerIP,
+		"server_number":     &config.ServerNumber,
+		"template_id":       &config.TemplateID,
+		"whitelist_hos":     &config.WhitelistHOS,
+		"filter_ipv6":       &config.FilterIPv6,
+		"rule":              &config.Rule,
+		"output_rule":       &config.OutputRule,
+		"named_rule":        &config.NamedRule,
+		"named_output_rule": &config.NamedOutputRule,
+	} {
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), target)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateFirewallServer(config.ServerIP, config.ServerNumber)...)
+
+	rule := len(config.Rule.Elements()) > 0 || config.Rule.IsUnknown()
+	outputRule := len(config.OutputRule.Elements()) > 0 || config.OutputRule.IsUnknown()
+	namedRule := len(config.NamedRule.Elements()) > 0 || config.NamedRule.IsUnknown()
+	namedOutputRule := len(config.NamedOutputRule.Elements()) > 0 || config.NamedOutputRule.IsUnknown()
+	if rule && namedRule {
+		resp.Diagnostics.AddAttributeError(path.Root("named_rule"), "Conflicting firewall configuration",
+			"named_rule cannot be combined with rule blocks")
+	}
+	if outputRule && namedOutputRule {
+		resp.Diagnostics.AddAttributeError(path.Root("named_output_rule"), "Conflicting firewall configuration",
+			"named_output_rule cannot be combined with output_rule blocks")
 	}
 
-	d.Set("active", active)
-	d.Set("rule", rules)
-	d.Set("server_ip", firewall.IP)
-	d.Set("whitelist_hos", firewall.WhitelistHetznerServices)
-	d.SetId(firewall.IP)
+	switch {
+	case config.TemplateID.IsUnknown():
+	case config.TemplateID.IsNull():
+		if config.WhitelistHOS.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("whitelist_hos"), "Missing whitelist_hos",
+				"whitelist_hos is required unless the firewall is configured by template_id")
+		}
+		if !rule && !namedRule {
+			resp.Diagnostics.AddAttributeError(path.Root("rule"), "Missing rule blocks",
+				"at least one rule block or named_rule is required unless the firewall is configured by template_id")
+		}
+	default:
+		for _, attribute := range []struct {
+			name       string
+			configured bool
+		}{
+			{"whitelist_hos", !config.WhitelistHOS.IsNull()},
+			{"filter_ipv6", !config.FilterIPv6.IsNull()},
+			{"rule", rule},
+			{"output_rule", outputRule},
+			{"named_rule", namedRule},
+			{"named_output_rule", namedOutputRule},
+		} {
+			if attribute.configured {
+				resp.Diagnostics.AddAttributeError(path.Root(attribute.name), "Conflicting firewall configuration",
+					fmt.Sprintf("%s is taken from the template and cannot be combined with template_id", attribute.name))
+			}
+		}
+	}
 
-	results := make([]*schema.ResourceData, 1)
-	results[0] = d
-	return results, nil
+	resp.Diagnostics.Append(validateFirewallRuleBlocks(ctx, req.Config)...)
 }
 
-func resourceFirewallCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(HetznerRobotClient)
+// ModifyPlan takes whitelist_hos and filter_ipv6 from the template, which are only
+// known after it was applied.
+func (r *firewallResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
 
-	serverIP := d.Get("server_ip").(string)
+	var templateID, priorTemplateID types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("template_id"), &templateID)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("template_id"), &priorTemplateID)...)
+	}
+	if resp.Diagnostics.HasError() || templateID.IsNull() {
+		return
+	}
 
-	status := "disabled"
-	if d.Get("active").(bool) {
-		status = "active"
+	for _, name := range []string{"whitelist_hos", "filter_ipv6"} {
+		value := types.BoolUnknown()
+		if templateID.Equal(priorTemplateID) {
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root(name), &value)...)
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), value)...)
 	}
+}
 
-	rules := make([]HetznerRobotFirewallRule, 0)
-	for _, ruleMap := range d.Get("rule").([]interface{}) {
-		ruleProperties := ruleMap.(map[string]interface{})
-		rules = append(rules, HetznerRobotFirewallRule{
-			Name:     ruleProperties["name"].(string),
-			SrcIP:    ruleProperties["src_ip"].(string),
-			SrcPort:  ruleProperties["src_port"].(string),
-			DstIP:    ruleProperties["dst_ip"].(string),
-			DstPort:  ruleProperties["dst_port"].(string),
-			Protocol: ruleProperties["protocol"].(string),
-			TCPFlags: ruleProperties["tcp_flags"].(string),
-			Action:   ruleProperties["action"].(string),
-		})
+func (r *firewallResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// the firewall is read by ID, which becomes the main IP once it was read
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	if serverNumber, err := strconv.Atoi(req.ID); err == nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("server_number"), serverNumber)...)
+	} else {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("server_ip"), req.ID)...)
 	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("on_destroy"), firewallOnDestroyClear)...)
+}
 
-	if err := c.setFirewall(ctx, HetznerRobotFirewall{
-		IP:                       serverIP,
-		WhitelistHetznerServices: d.Get("whitelist_hos").(bool),
-		Status:                   status,
-		Rules:                    HetznerRobotFirewallRules{Input: rules},
-	}); err != nil {
-		return diag.FromErr(err)
+func (r *firewallResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan firewallResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	d.SetId(serverIP)
+	timeout, diags := plan.Timeouts.Create(ctx, defaultFirewallTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	resp.Diagnostics.Append(r.setFirewall(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	plan.ID = plan.ServerIP
 
-	return diags
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceFirewallRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(HetznerRobotClient)
+func (r *firewallResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state firewallResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	serverIP := d.Id()
+	server := state.ID.ValueString()
 
-	firewall, err := c.getFirewall(ctx, serverIP)
+	firewall, err := r.client.GetFirewall(ctx, server)
+	if errors.Is(err, robot.ErrNotFound) {
+		resp.Diagnostics.Append(removeGoneResource(ctx, &resp.State, "Firewall of server", server, err)...)
+		return
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to read firewall of server %s", server), err.Error())
+		return
+	}
+
+	// the status Robot is moving to is unknown while it applies a change
+	if firewall.Status != firewallStatusInProcess {
+		state.Active = types.BoolValue(firewall.Status == "active")
+	}
+	if state.TemplateID.IsNull() {
+		state.Rule, state.NamedRule = flattenFirewallChain(firewall.Rules.Input, state.NamedRule)
+		state.OutputRule, state.NamedOutputRule = flattenFirewallChain(firewall.Rules.Output, state.NamedOutputRule)
+	} else {
+		// rules of a templated firewall are managed by the template
+		state.clearRules()
+	}
+	state.ID = types.StringValue(firewall.IP)
+	state.ServerIP = types.StringValue(firewall.IP)
+	state.ServerNumber = types.Int64Value(int64(firewall.ServerNumber))
+	state.WhitelistHOS = types.BoolValue(firewall.WhitelistHetznerServices)
+	state.FilterIPv6 = types.BoolValue(firewall.FilterIPv6)
+	if state.OnDestroy.IsNull() {
+		// firewalls created before on_destroy existed
+		state.OnDestroy = types.StringValue(firewallOnDestroyClear)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *firewallResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan firewallResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	active := false
-	if firewall.Status == "active" {
-		active = true
+	timeout, diags := plan.Timeouts.Update(ctx, defaultFirewallTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	rules := make([]map[string]interface{}, 0)
-	for _, rule := range firewall.Rules.Input {
-		r := map[string]interface{}{
-			"name":      rule.Name,
-			"src_ip":    rule.SrcIP,
-			"src_port":  rule.SrcPort,
-			"dst_ip":    rule.DstIP,
-			"dst_port":  rule.DstPort,
-			"protocol":  rule.Protocol,
-			"tcp_flags": rule.TCPFlags,
-			"action":    rule.Action,
+	resp.Diagnostics.Append(r.setFirewall(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *firewallResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state firewallResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	serverIP := state.ServerIP.ValueString()
+	onDestroy := state.OnDestroy.ValueString()
+	if onDestroy == firewallOnDestroyKeep {
+		tflog.Info(ctx, "keeping firewall on destroy", map[string]interface{}{"server": serverIP})
+		return
+	}
+
+	timeout, diags := state.Timeouts.Delete(ctx, defaultFirewallTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	unlock := firewallLocks.Lock(serverIP)
+	defer unlock()
+
+	firewall, err := waitForFirewall(ctx, r.client, serverIP)
+	if err == nil {
+		if onDestroy == firewallOnDestroyDisable {
+			firewall.Status = "disabled"
+			err = r.client.SetFirewall(ctx, *firewall)
+		} else {
+			err = r.client.DeleteFirewall(ctx, serverIP)
 		}
-		rules = append(rules, r)
 	}
-	d.Set("active", active)
-	d.Set("rule", rules)
-	d.Set("server_ip", firewall.IP)
-	d.Set("whitelist_hos", firewall.WhitelistHetznerServices)
+	if err == nil {
+		_, err = waitForFirewall(ctx, r.client, serverIP)
+	}
+	if err != nil && !errors.Is(err, robot.ErrNotFound) {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to %s firewall of server %s", onDestroy, serverIP), err.Error())
+	}
+}
 
-	// Warning or errors can be collected in a slice type
+// validateFirewallServer checks that a firewall is identified by exactly one of
+// server_ip and server_number.
+func validateFirewallServer(serverIP types.String, serverNumber types.Int64) diag.Diagnostics {
 	var diags diag.Diagnostics
-
+	if !serverIP.IsUnknown() && !serverNumber.IsUnknown() && serverIP.IsNull() == serverNumber.IsNull() {
+		diags.AddAttributeError(path.Root("server_ip"), "Invalid server",
+			"exactly one of server_ip and server_number is required")
+	}
 	return diags
 }
 
-func resourceFirewallUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(HetznerRobotClient)
+// setFirewall applies plan and waits for Robot to finish, filling in the attributes
+// taken from a template.
+func (r *firewallResource) setFirewall(ctx context.Context, plan *firewallResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// changes are serialized by main IP, whichever identifier is configured
+	if plan.ServerIP.IsNull() || plan.ServerIP.IsUnknown() {
+		serverNumber := strconv.FormatInt(plan.ServerNumber.ValueInt64(), 10)
+		firewall, err := r.client.GetFirewall(ctx, serverNumber)
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Unable to set firewall for server %s", serverNumber), err.Error())
+			return diags
+		}
+		plan.ServerIP = types.StringValue(firewall.IP)
+	}
+	serverIP := plan.ServerIP.ValueString()
+
+	unlock := firewallLocks.Lock(serverIP)
+	defer unlock()
 
-	serverIP := d.Get("server_ip").(string)
+	// a change made outside of Terraform may still be in process
+	if _, err := waitForFirewall(ctx, r.client, serverIP); err != nil {
+		diags.AddError(fmt.Sprintf("Unable to set firewall for server %s", serverIP), err.Error())
+		return diags
+	}
 
 	status := "disabled"
-	if d.Get("active").(bool) {
+	if plan.Active.ValueBool() {
 		status = "active"
 	}
 
-	rules := make([]HetznerRobotFirewallRule, 0)
-	for _, ruleMap := range d.Get("rule").([]interface{}) {
-		ruleProperties := ruleMap.(map[string]interface{})
-		rules = append(rules, HetznerRobotFirewallRule{
-			Name:     ruleProperties["name"].(string),
-			SrcIP:    ruleProperties["src_ip"].(string),
-			SrcPort:  ruleProperties["src_port"].(string),
-			DstIP:    ruleProperties["dst_ip"].(string),
-			DstPort:  ruleProperties["dst_port"].(string),
-			Protocol: ruleProperties["protocol"].(string),
-			TCPFlags: ruleProperties["tcp_flags"].(string),
-			Action:   ruleProperties["action"].(string),
+	if !plan.TemplateID.IsNull() {
+		templateID := int(plan.TemplateID.ValueInt64())
+		if err := r.client.ApplyFirewallTemplate(ctx, serverIP, templateID, status); err != nil {
+			return apiErrorDiagnostics(fmt.Sprintf("Unable to apply firewall template %d to server %s", templateID, serverIP), err, firewallAPIFields)
+		}
+	} else {
+		err := r.client.SetFirewall(ctx, robot.Firewall{
+			IP:                       serverIP,
+			WhitelistHetznerServices: plan.WhitelistHOS.ValueBool(),
+			FilterIPv6:               plan.FilterIPv6.ValueBool(),
+			Status:                   status,
+			Rules: robot.FirewallRules{
+				Input:  expandFirewallRules(firewallChainRules(plan.Rule, plan.NamedRule)),
+				Output: expandFirewallRules(firewallChainRules(plan.OutputRule, plan.NamedOutputRule)),
+			},
 		})
+		if err != nil {
+			return apiErrorDiagnostics(fmt.Sprintf("Unable to set firewall for server %s", serverIP), err, firewallNamedRuleFieldPath(plan))
+		}
+	}
+
+	firewall, err := waitForFirewall(ctx, r.client, serverIP)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Unable to set firewall for server %s", serverIP), err.Error())
+		return diags
+	}
+	plan.ServerNumber = types.Int64Value(int64(firewall.ServerNumber))
+	if !plan.TemplateID.IsNull() {
+		plan.WhitelistHOS = types.BoolValue(firewall.WhitelistHetznerServices)
+		plan.FilterIPv6 = types.BoolValue(firewall.FilterIPv6)
+		plan.clearRules()
 	}
+	return diags
+}
+
+// clearRules empties all rule blocks, e.g. of a firewall configured by a template.
+func (m *firewallResourceModel) clearRules() {
+	m.Rule = []firewallRuleModel{}
+	m.OutputRule = []firewallRuleModel{}
+	m.NamedRule = nil
+	m.NamedOutputRule = nil
+}
+
+// waitForFirewall polls the firewall of server until Robot finished applying the
+// last change, or ctx expires.
+func waitForFirewall(ctx context.Context, client *robot.Client, server string) (*robot.Firewall, error) {
+	ticker := time.NewTicker(firewallPollInterval)
+	defer ticker.Stop()
 
-	if err := c.setFirewall(ctx, HetznerRobotFirewall{
-		IP:                       serverIP,
-		WhitelistHetznerServices: d.Get("whitelist_hos").(bool),
-		Status:                   status,
-		Rules:                    HetznerRobotFirewallRules{Input: rules},
-	}); err != nil {
-		return diag.FromErr(err)
+	for {
+		firewall, err := client.GetFirewall(ctx, server)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timed out waiting for the firewall of server %s to leave %q: %w", server, firewallStatusInProcess, ctx.Err())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if firewall.Status != firewallStatusInProcess {
+			return firewall, nil
+		}
+
+		tflog.Debug(ctx, "waiting for firewall change", map[string]interface{}{"server": server})
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
 	}
+}
 
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+// firewallChainRules returns the rules of a chain in evaluation order, from either
+// its rule blocks or its named rules.
+func firewallChainRules(rules []firewallRuleModel, named map[string]firewallNamedRuleModel) []firewallRuleModel {
+	if len(named) == 0 {
+		return rules
+	}
 
-	return diags
+	result := make([]firewallRuleModel, 0, len(named))
+	for _, name := range sortFirewallNamedRules(named) {
+		rule := named[name].rule(name)
+		rule.IPVersion = defaultFirewallRuleIPVersion(rule)
+		result = append(result, rule)
+	}
+	return result
 }
 
-func resourceFirewallDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+// flattenFirewallChain converts the rules of a chain into rule blocks, or into named
+// rules when the chain was configured by them. Rules changed outside Terraform to
+// share a name or to have none cannot be keyed by name and end up in rule blocks,
+// which shows up as a diff instead of silently dropping rules.
+func flattenFirewallChain(rules []robot.FirewallRule, prior map[string]firewallNamedRuleModel) ([]firewallRuleModel, map[string]firewallNamedRuleModel) {
+	if len(prior) == 0 {
+		return flattenFirewallRules(rules), prior
+	}
+	if !firewallRuleNamesUnique(rules) {
+		return flattenFirewallRules(rules), nil
+	}
 
-	return diags
+	result := make(map[string]firewallNamedRuleModel, len(rules))
+	var previous int64
+	for idx, rule := range flattenFirewallRules(rules) {
+		name := rule.Name.ValueString()
+		priorRule, ok := prior[name]
+
+		// keep the configured priorities as long as they match the order in Robot
+		priority := previous + 1
+		if ok && (idx == 0 || priorRule.Priority.ValueInt64() >= priority) {
+			priority = priorRule.Priority.ValueInt64()
+		}
+		previous = priority
+
+		result[name] = firewallNamedRuleModel{
+			Priority:  types.Int64Value(priority),
+			IPVersion: rule.IPVersion,
+			DstIP:     rule.DstIP,
+			DstPort:   rule.DstPort,
+			SrcIP:     rule.SrcIP,
+			SrcPort:   rule.SrcPort,
+			Protocol:  rule.Protocol,
+			TCPFlags:  rule.TCPFlags,
+			Action:    rule.Action,
+		}
+	}
+	return []firewallRuleModel{}, result
+}
+
+// firewallRuleNamesUnique reports whether every rule has a name of its own.
+func firewallRuleNamesUnique(rules []robot.FirewallRule) bool {
+	names := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule.Name == "" || names[rule.Name] {
+			return false
+		}
+		names[rule.Name] = true
+	}
+	return true
+}
+
+// sortFirewallNamedRules returns the names of rules ordered by priority.
+func sortFirewallNamedRules(rules map[string]firewallNamedRuleModel) []string {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if rules[names[i]].Priority.ValueInt64() != rules[names[j]].Priority.ValueInt64() {
+			return rules[names[i]].Priority.ValueInt64() < rules[names[j]].Priority.ValueInt64()
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// defaultFirewallRuleIPVersion returns the ip_version of rule, defaulting to the
+// family of its addresses.
+func defaultFirewallRuleIPVersion(rule firewallRuleModel) types.String {
+	if !rule.IPVersion.IsNull() {
+		return rule.IPVersion
+	}
+	for _, address := range []types.String{rule.SrcIP, rule.DstIP} {
+		if family := ipFamily(address.ValueString()); family != "" {
+			return types.StringValue(family)
+		}
+	}
+	return types.StringNull()
+}
+
+// firewallNamedRuleFieldPath points Robot errors about rules sent from named rules at
+// their map elements.
+func firewallNamedRuleFieldPath(plan *firewallResourceModel) apiFieldPath {
+	return func(field []string) path.Path {
+		attributePath := firewallAPIFields(field)
+		if field[0] != "rules" || len(field) < 3 {
+			return attributePath
+		}
+
+		attribute, named := "named_rule", plan.NamedRule
+		if field[1] == "output" {
+			attribute, named = "named_output_rule", plan.NamedOutputRule
+		}
+		idx, err := strconv.Atoi(field[2])
+		if len(named) == 0 || err != nil || idx >= len(named) {
+			return attributePath
+		}
+
+		attributePath = path.Root(attribute).AtMapKey(sortFirewallNamedRules(named)[idx])
+		if len(field) > 3 && field[3] != "name" {
+			attributePath = attributePath.AtName(field[3])
+		}
+		return attributePath
+	}
+}
+
+func flattenFirewallRules(rules []robot.FirewallRule) []firewallRuleModel {
+	result := make([]firewallRuleModel, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, firewallRuleModel{
+			IPVersion: optionalString(rule.IPVersion),
+			Name:      optionalString(rule.Name),
+			SrcIP:     optionalString(rule.SrcIP),
+			SrcPort:   optionalString(rule.SrcPort),
+			DstIP:     optionalString(rule.DstIP),
+			DstPort:   optionalString(rule.DstPort),
+			Protocol:  optionalString(rule.Protocol),
+			TCPFlags:  optionalString(rule.TCPFlags),
+			Action:    types.StringValue(rule.Action),
+		})
+	}
+	return result
+}
+
+func expandFirewallRules(rules []firewallRuleModel) []robot.FirewallRule {
+	result := make([]robot.FirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, robot.FirewallRule{
+			IPVersion: rule.IPVersion.ValueString(),
+			Name:      rule.Name.ValueString(),
+			SrcIP:     rule.SrcIP.ValueString(),
+			SrcPort:   rule.SrcPort.ValueString(),
+			DstIP:     rule.DstIP.ValueString(),
+			DstPort:   rule.DstPort.ValueString(),
+			Protocol:  rule.Protocol.ValueString(),
+			TCPFlags:  rule.TCPFlags.ValueString(),
+			Action:    rule.Action.ValueString(),
+		})
+	}
+	return result
+}
+
+// firewallRuleIPVersion plans an unset ip_version as the family of the rule's
+// addresses, Robot only accepts addresses on rules limited to one family.
+type firewallRuleIPVersion struct{}
+
+func (m firewallRuleIPVersion) Description(ctx context.Context) string {
+	return "Defaults ip_version to the family of src_ip or dst_ip."
+}
+
+func (m firewallRuleIPVersion) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m firewallRuleIPVersion) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+
+	resp.PlanValue = types.StringNull()
+	for _, field := range []string{"src_ip", "dst_ip"} {
+		var address types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, req.Path.ParentPath().AtName(field), &address)...)
+		if address.IsUnknown() {
+			resp.PlanValue = types.StringUnknown()
+			return
+		}
+		if family := ipFamily(address.ValueString()); family != "" {
+			resp.PlanValue = types.StringValue(family)
+			return
+		}
+	}
 }
@@ -0,0 +1,200 @@
+package hetznerrobot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+var firewallTemplateAPIFields = firewallFieldMap(map[string]string{
+	"filter_ipv6":   "filter_ipv6",
+	"is_default":    "is_default",
+	"name":          "name",
+	"whitelist_hos": "whitelist_hos",
+})
+
+type firewallTemplateResource struct {
+	client *robot.Client
+}
+
+type firewallTemplateResourceModel struct {
+	ID           types.String        `tfsdk:"id"`
+	Name         types.String        `tfsdk:"name"`
+	WhitelistHOS types.Bool          `tfsdk:"whitelist_hos"`
+	FilterIPv6   types.Bool          `tfsdk:"filter_ipv6"`
+	IsDefault    types.Bool          `tfsdk:"is_default"`
+	Rule         []firewallRuleModel `tfsdk:"rule"`
+	OutputRule   []firewallRuleModel `tfsdk:"output_rule"`
+}
+
+func newFirewallTemplateResource() resource.Resource {
+	return &firewallTemplateResource{}
+}
+
+func (r *firewallTemplateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firewall_template"
+}
+
+func (r *firewallTemplateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Template name",
+			},
+			"whitelist_hos": schema.BoolAttribute{
+				Required:    true,
+				Description: "Allow Hetzner services",
+			},
+			"filter_ipv6": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Filter IPv6 traffic, when false all IPv6 traffic passes the firewall",
+			},
+			"is_default": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Apply the template to newly ordered servers",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rule":        firewallRuleBlock("Incoming traffic rules"),
+			"output_rule": firewallRuleBlock("Outgoing traffic rules"),
+		},
+	}
+}
+
+func (r *firewallTemplateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = resourceClient(req, resp)
+}
+
+func (r *firewallTemplateResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateFirewallRuleBlocks(ctx, req.Config)...)
+}
+
+func (r *firewallTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if _, err := strconv.Atoi(req.ID); err != nil {
+		resp.Diagnostics.AddError("Invalid firewall template ID", fmt.Sprintf("invalid firewall template ID %q: %s", req.ID, err))
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *firewallTemplateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan firewallTemplateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+	template, err := r.client.CreateFirewallTemplate(ctx, plan.template(0))
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to create firewall template %q", name), err, firewallTemplateAPIFields)...)
+		return
+	}
+	plan.ID = types.StringValue(strconv.Itoa(template.ID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *firewallTemplateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state firewallTemplateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateID, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid firewall template ID", fmt.Sprintf("invalid firewall template ID %q: %s", state.ID.ValueString(), err))
+		return
+	}
+	template, err := r.client.GetFirewallTemplate(ctx, templateID)
+	if errors.Is(err, robot.ErrNotFound) {
+		resp.Diagnostics.Append(removeGoneResource(ctx, &resp.State, "Firewall template", state.ID.ValueString(), err)...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to find firewall template with ID %d", templateID), err.Error())
+		return
+	}
+
+	state.Name = types.StringValue(template.Name)
+	state.WhitelistHOS = types.BoolValue(template.WhitelistHetznerServices)
+	state.FilterIPv6 = types.BoolValue(template.FilterIPv6)
+	state.IsDefault = types.BoolValue(template.IsDefault)
+	state.Rule = flattenFirewallRules(template.Rules.Input)
+	state.OutputRule = flattenFirewallRules(template.Rules.Output)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *firewallTemplateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan firewallTemplateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateID, err := strconv.Atoi(plan.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid firewall template ID", fmt.Sprintf("invalid firewall template ID %q: %s", plan.ID.ValueString(), err))
+		return
+	}
+	if _, err := r.client.UpdateFirewallTemplate(ctx, plan.template(templateID)); err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to update firewall template %d", templateID), err, firewallTemplateAPIFields)...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *firewallTemplateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state firewallTemplateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	templateID, err := strconv.Atoi(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid firewall template ID", fmt.Sprintf("invalid firewall template ID %q: %s", state.ID.ValueString(), err))
+		return
+	}
+	err = r.client.DeleteFirewallTemplate(ctx, templateID)
+	if err != nil && !errors.Is(err, robot.ErrNotFound) {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to delete firewall template with ID %d", templateID), err.Error())
+	}
+}
+
+func (m firewallTemplateResourceModel) template(id int) robot.FirewallTemplate {
+	return robot.FirewallTemplate{
+		ID:                       id,
+		Name:                     m.Name.ValueString(),
+		WhitelistHetznerServices: m.WhitelistHOS.ValueBool(),
+		FilterIPv6:               m.FilterIPv6.ValueBool(),
+		IsDefault:                m.IsDefault.ValueBool(),
+		Rules: robot.FirewallRules{
+			Input:  expandFirewallRules(m.Rule),
+			Output: expandFirewallRules(m.OutputRule),
+		},
+	}
+}
@@ -0,0 +1,122 @@
+package hetznerrobot
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+)
+
+func TestAccResourceFirewallTemplate_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t, fake),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "hetzner-robot_firewall_template" "test" {
+  name          = "standard"
+  whitelist_hos = true
+
+  rule {
+    name     = "Allow ssh"
+    dst_port = "22"
+    protocol = "tcp"
+    action   = "accept"
+  }
+}
+
+resource "hetzner-robot_firewall" "test" {
+  server_ip   = "192.0.2.10"
+  active      = true
+  template_id = hetzner-robot_firewall_template.test.id
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_firewall_template.test", "rule.#", "1"),
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "whitelist_hos", "true"),
+				),
+			},
+			{
+				ResourceName:      "hetzner-robot_firewall_template.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceFirewallTemplate(t *testing.T) {
+	fake := robotfake.New(t)
+	r := newTestResource(t, newFirewallTemplateResource(), fake)
+
+	model := firewallTemplateResourceModel{
+		Name:         types.StringValue("standard"),
+		WhitelistHOS: types.BoolValue(true),
+		FilterIPv6:   types.BoolValue(false),
+		IsDefault:    types.BoolValue(true),
+		Rule:         []firewallRuleModel{{Name: types.StringValue("Allow ssh"), DstPort: types.StringValue("22"), Protocol: types.StringValue("tcp"), Action: types.StringValue("accept")}},
+		OutputRule:   []firewallRuleModel{{Name: types.StringValue("Allow all"), Action: types.StringValue("accept")}},
+	}
+	state, diags := r.create(model)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	r.get(state, &model)
+	if model.ID.ValueString() == "" {
+		t.Fatal("expected an id")
+	}
+
+	model.Name = types.StringValue("renamed")
+	model.FilterIPv6 = types.BoolValue(true)
+	model.Rule = append(model.Rule, firewallRuleModel{Name: types.StringValue("Deny others"), Action: types.StringValue("discard")})
+	if state, diags = r.update(state, model); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+
+	imported, diags := r.importState(model.ID.ValueString())
+	if diags.HasError() {
+		t.Fatalf("import: %v", diags)
+	}
+	if imported, diags = r.read(imported); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	var read firewallTemplateResourceModel
+	r.get(imported, &read)
+	if read.Name.ValueString() != "renamed" || !read.FilterIPv6.ValueBool() || !read.IsDefault.ValueBool() || len(read.Rule) != 2 || len(read.OutputRule) != 1 {
+		t.Errorf("unexpected template after import: %+v", read)
+	}
+
+	if diags := r.delete(state); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() || !state.Raw.IsNull() {
+		t.Errorf("expected a deleted template to be removed from state, got %v", diags)
+	}
+}
+
+// TestFirewallAPIFields makes sure Robot errors only point at attributes the resource
+// has.
+func TestFirewallAPIFields(t *testing.T) {
+	for _, tc := range []struct {
+		fieldPath apiFieldPath
+		field     string
+		want      path.Path
+	}{
+		{fieldPath: firewallAPIFields, field: "name", want: path.Empty()},
+		{fieldPath: firewallAPIFields, field: "status", want: path.Root("active")},
+		{fieldPath: firewallAPIFields, field: "rules[output][1][dst_port]", want: path.Root("output_rule").AtListIndex(1).AtName("dst_port")},
+		{fieldPath: firewallTemplateAPIFields, field: "name", want: path.Root("name")},
+		{fieldPath: firewallTemplateAPIFields, field: "template_id", want: path.Empty()},
+		{fieldPath: firewallTemplateAPIFields, field: "rules[input][0][src_ip]", want: path.Root("rule").AtListIndex(0).AtName("src_ip")},
+	} {
+		if got := tc.fieldPath(splitAPIField(tc.field)); !got.Equal(tc.want) {
+			t.Errorf("%s: expected %s, got %s", tc.field, tc.want, got)
+		}
+	}
+}
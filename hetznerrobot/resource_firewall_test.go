@@ -0,0 +1,603 @@
+package hetznerrobot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
+)
+
+func TestAccResourceFirewall_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t, fake),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "hetzner-robot_firewall" "test" {
+  server_ip     = "192.0.2.10"
+  active        = true
+  whitelist_hos = true
+
+  rule {
+    name     = "Allow ssh"
+    dst_port = "22"
+    protocol = "tcp"
+    action   = "accept"
+  }
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "id", "192.0.2.10"),
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "rule.#", "1"),
+					resource.TestCheckResourceAttr("hetzner-robot_firewall.test", "rule.0.dst_port", "22"),
+				),
+			},
+			{
+				ResourceName:      "hetzner-robot_firewall.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceFirewall(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	state, diags := r.create(firewallResourceModel{
+		Timeouts:     testFirewallTimeouts,
+		ServerIP:     types.StringValue("192.0.2.10"),
+		Active:       types.BoolValue(true),
+		WhitelistHOS: types.BoolValue(true),
+		Rule: []firewallRuleModel{
+			{Name: types.StringValue("Allow ssh"), DstPort: types.StringValue("22"), Protocol: types.StringValue("tcp"), Action: types.StringValue("accept")},
+			{Name: types.StringValue("Deny others"), Action: types.StringValue("discard")},
+		},
+	})
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	var model firewallResourceModel
+	r.get(state, &model)
+	if model.ID.ValueString() != "192.0.2.10" {
+		t.Errorf("unexpected id %s", model.ID)
+	}
+
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r.get(state, &model)
+	if len(model.Rule) != 2 || model.Rule[0].DstPort.ValueString() != "22" || !model.Rule[1].DstPort.IsNull() {
+		t.Errorf("unexpected rules: %v", model.Rule)
+	}
+
+	fake.CancelServer(321)
+	state, diags = r.read(state)
+	if diags.HasError() || len(diags) != 1 || !state.Raw.IsNull() {
+		t.Errorf("expected a gone firewall to be removed from state with a warning, got %v", diags)
+	}
+}
+
+func TestResourceFirewallImport(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	for _, id := range []string{"192.0.2.10", "321"} {
+		state, diags := r.importState(id)
+		if diags.HasError() {
+			t.Fatalf("import %s: %v", id, diags)
+		}
+		if state, diags = r.read(state); diags.HasError() {
+			t.Fatalf("read %s: %v", id, diags)
+		}
+		var model firewallResourceModel
+		r.get(state, &model)
+		if model.ID.ValueString() != "192.0.2.10" || model.ServerIP.ValueString() != "192.0.2.10" || model.ServerNumber.ValueInt64() != 321 || model.Rule == nil {
+			t.Errorf("unexpected state after importing %s: %+v", id, model)
+		}
+	}
+}
+
+func TestResourceFirewallServerNumber(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	state, diags := r.create(firewallResourceModel{
+		Timeouts:     testFirewallTimeouts,
+		ServerIP:     types.StringUnknown(),
+		ServerNumber: types.Int64Value(321),
+		Active:       types.BoolValue(true),
+		WhitelistHOS: types.BoolValue(true),
+		Rule:         []firewallRuleModel{{Name: types.StringValue("Allow all"), Action: types.StringValue("accept")}},
+	})
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	var model firewallResourceModel
+	r.get(state, &model)
+	if model.ID.ValueString() != "192.0.2.10" || model.ServerIP.ValueString() != "192.0.2.10" {
+		t.Errorf("expected the main IP to be filled in, got %+v", model)
+	}
+
+	firewall, err := testClient(fake).GetFirewall(context.Background(), "321")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firewall.ServerNumber != 321 || len(firewall.Rules.Input) != 1 {
+		t.Errorf("unexpected firewall %+v", firewall)
+	}
+}
+
+func TestResourceFirewallInvalidRule(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	_, diags := r.create(firewallResourceModel{
+		Timeouts:     testFirewallTimeouts,
+		ServerIP:     types.StringValue("192.0.2.10"),
+		Active:       types.BoolValue(true),
+		WhitelistHOS: types.BoolValue(true),
+		Rule: []firewallRuleModel{
+			{Name: types.StringValue("ok"), Action: types.StringValue("accept")},
+			{Name: types.StringValue("broken"), SrcIP: types.StringValue("300.0.0.1"), Action: types.StringValue("accept")},
+		},
+	})
+	if len(diags) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", diags)
+	}
+	if want := path.Root("rule").AtListIndex(1).AtName("src_ip"); !diags[0].(diag.DiagnosticWithPath).Path().Equal(want) {
+		t.Errorf("expected the diagnostic to point at %s, got %s", want, diags[0].(diag.DiagnosticWithPath).Path())
+	}
+}
+
+func TestResourceFirewallIPv6(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	state, diags := r.create(firewallResourceModel{
+		Timeouts:     testFirewallTimeouts,
+		ServerIP:     types.StringValue("192.0.2.10"),
+		Active:       types.BoolValue(true),
+		WhitelistHOS: types.BoolValue(true),
+		FilterIPv6:   types.BoolValue(true),
+		Rule: []firewallRuleModel{
+			{IPVersion: types.StringValue("ipv6"), SrcIP: types.StringValue("2001:db8::/32"), Action: types.StringValue("accept")},
+			{IPVersion: types.StringValue("ipv4"), SrcIP: types.StringValue("198.51.100.0/24"), Action: types.StringValue("accept")},
+			{DstPort: types.StringValue("443"), Protocol: types.StringValue("tcp"), Action: types.StringValue("accept")},
+		},
+	})
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	var model firewallResourceModel
+	r.get(state, &model)
+	if !model.FilterIPv6.ValueBool() {
+		t.Error("expected filter_ipv6 to be enabled")
+	}
+	if len(model.Rule) != 3 || model.Rule[0].IPVersion.ValueString() != "ipv6" || model.Rule[1].IPVersion.ValueString() != "ipv4" || !model.Rule[2].IPVersion.IsNull() {
+		t.Errorf("unexpected rules: %v", model.Rule)
+	}
+}
+
+func TestResourceFirewallOutputRules(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	model := firewallResourceModel{
+		Timeouts:     testFirewallTimeouts,
+		ServerIP:     types.StringValue("192.0.2.10"),
+		Active:       types.BoolValue(true),
+		WhitelistHOS: types.BoolValue(true),
+		Rule:         []firewallRuleModel{{Name: types.StringValue("Allow all"), Action: types.StringValue("accept")}},
+		OutputRule: []firewallRuleModel{
+			{Name: types.StringValue("Allow dns"), DstPort: types.StringValue("53"), Protocol: types.StringValue("udp"), Action: types.StringValue("accept")},
+			{Name: types.StringValue("Deny others"), Action: types.StringValue("discard")},
+		},
+	}
+	state, diags := r.create(model)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r.get(state, &model)
+	if len(model.OutputRule) != 2 || model.OutputRule[0].DstPort.ValueString() != "53" || model.OutputRule[1].Action.ValueString() != "discard" {
+		t.Errorf("unexpected output rules: %v", model.OutputRule)
+	}
+
+	model.OutputRule = []firewallRuleModel{{Name: types.StringValue("broken"), DstIP: types.StringValue("300.0.0.1"), Action: types.StringValue("accept")}}
+	_, diags = r.update(state, model)
+	if want := path.Root("output_rule").AtListIndex(0).AtName("dst_ip"); len(diags) != 1 || !diags[0].(diag.DiagnosticWithPath).Path().Equal(want) {
+		t.Errorf("expected a diagnostic pointing at %s, got %v", want, diags)
+	}
+}
+
+func TestResourceFirewallTemplateID(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	template, err := testClient(fake).CreateFirewallTemplate(context.Background(), robot.FirewallTemplate{
+		Name:       "standard",
+		FilterIPv6: true,
+		Rules:      robot.FirewallRules{Input: []robot.FirewallRule{{Name: "Allow ssh", DstPort: "22", Protocol: "tcp", Action: "accept"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	model := firewallResourceModel{
+		Timeouts:     testFirewallTimeouts,
+		ServerIP:     types.StringValue("192.0.2.10"),
+		Active:       types.BoolValue(true),
+		WhitelistHOS: types.BoolUnknown(),
+		FilterIPv6:   types.BoolUnknown(),
+		TemplateID:   types.Int64Value(int64(template.ID)),
+	}
+	state, diags := r.create(model)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r.get(state, &model)
+	if model.WhitelistHOS.ValueBool() || !model.FilterIPv6.ValueBool() || len(model.Rule) != 0 {
+		t.Errorf("unexpected state after applying the template: %+v", model)
+	}
+
+	firewall, err := testClient(fake).GetFirewall(context.Background(), "192.0.2.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firewall.Status != "active" || len(firewall.Rules.Input) != 1 || firewall.Rules.Input[0].DstPort != "22" {
+		t.Errorf("expected the template rules to be applied, got %+v", firewall)
+	}
+}
+
+func TestResourceFirewallValidateConfig(t *testing.T) {
+	r := newTestResource(t, newFirewallResource(), robotfake.New(t))
+	allow := []firewallRuleModel{{Action: types.StringValue("accept")}}
+	serverIP := types.StringValue("192.0.2.10")
+
+	for _, tc := range []struct {
+		name  string
+		model firewallResourceModel
+		want  []path.Path
+	}{
+		{
+			name:  "rules",
+			model: firewallResourceModel{Timeouts: testFirewallTimeouts, ServerIP: serverIP, WhitelistHOS: types.BoolValue(true), Rule: allow},
+		},
+		{
+			name:  "server number",
+			model: firewallResourceModel{Timeouts: testFirewallTimeouts, ServerNumber: types.Int64Value(321), WhitelistHOS: types.BoolValue(true), Rule: allow},
+		},
+		{
+			name:  "template",
+			model: firewallResourceModel{Timeouts: testFirewallTimeouts, ServerIP: serverIP, TemplateID: types.Int64Value(1)},
+		},
+		{
+			name:  "nothing",
+			model: firewallResourceModel{Timeouts: testFirewallTimeouts},
+			want:  []path.Path{path.Root("server_ip"), path.Root("whitelist_hos"), path.Root("rule")},
+		},
+		{
+			name:  "server ip and number",
+			model: firewallResourceModel{Timeouts: testFirewallTimeouts, ServerIP: serverIP, ServerNumber: types.Int64Value(321), WhitelistHOS: types.BoolValue(true), Rule: allow},
+			want:  []path.Path{path.Root("server_ip")},
+		},
+		{
+			name: "named rules",
+			model: firewallResourceModel{Timeouts: testFirewallTimeouts, ServerIP: serverIP, WhitelistHOS: types.BoolValue(true), NamedRule: map[string]firewallNamedRuleModel{
+				"allow": {Priority: types.Int64Value(10), Action: types.StringValue("accept")},
+			}},
+		},
+		{
+			name: "rules and named rules",
+			model: firewallResourceModel{Timeouts: testFirewallTimeouts, ServerIP: serverIP, WhitelistHOS: types.BoolValue(true), Rule: allow, NamedRule: map[string]firewallNamedRuleModel{
+				"allow": {Priority: types.Int64Value(10), Action: types.StringValue("accept")},
+			}},
+			want: []path.Path{path.Root("named_rule")},
+		},
+		{
+			name:  "template and rules",
+			model: firewallResourceModel{Timeouts: testFirewallTimeouts, ServerIP: serverIP, TemplateID: types.Int64Value(1), WhitelistHOS: types.BoolValue(true), OutputRule: allow},
+			want:  []path.Path{path.Root("whitelist_hos"), path.Root("output_rule")},
+		},
+	} {
+		diags := r.validate(tc.model)
+		if len(diags) != len(tc.want) {
+			t.Errorf("%s: expected %d diagnostics, got %v", tc.name, len(tc.want), diags)
+			continue
+		}
+		for i, want := range tc.want {
+			if got := diags[i].(diag.DiagnosticWithPath).Path(); !got.Equal(want) {
+				t.Errorf("%s: expected a diagnostic at %s, got %s", tc.name, want, got)
+			}
+		}
+	}
+}
+
+// testFirewallTimeouts is a timeouts block left out of the configuration.
+var testFirewallTimeouts = timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+	"create": types.StringType,
+	"update": types.StringType,
+	"delete": types.StringType,
+})}
+
+func TestResourceFirewallWaitsWhileInProcess(t *testing.T) {
+	pollInterval := firewallPollInterval
+	firewallPollInterval = time.Millisecond
+	t.Cleanup(func() { firewallPollInterval = pollInterval })
+
+	fake := robotfake.New(t)
+	fake.PendingPolls = 3
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	model := func(name string) firewallResourceModel {
+		return firewallResourceModel{
+			ServerIP:     types.StringValue("192.0.2.10"),
+			Active:       types.BoolValue(true),
+			WhitelistHOS: types.BoolValue(true),
+			Rule:         []firewallRuleModel{{Name: types.StringValue(name), Action: types.StringValue("accept")}},
+			Timeouts:     testFirewallTimeouts,
+		}
+	}
+
+	// parallel changes of the same server are serialized instead of failing with
+	// FIREWALL_IN_PROCESS
+	var wg sync.WaitGroup
+	errs := make(chan diag.Diagnostics, 3)
+	for _, name := range []string{"one", "two", "three"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, diags := r.create(model(name))
+			errs <- diags
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for diags := range errs {
+		if diags.HasError() {
+			t.Errorf("create: %v", diags)
+		}
+	}
+
+	firewall, err := testClient(fake).GetFirewall(context.Background(), "192.0.2.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if firewall.Status != "active" {
+		t.Errorf("expected the last change to be applied when create returns, got status %q", firewall.Status)
+	}
+}
+
+func TestResourceFirewallTimeout(t *testing.T) {
+	pollInterval := firewallPollInterval
+	firewallPollInterval = time.Millisecond
+	t.Cleanup(func() { firewallPollInterval = pollInterval })
+
+	fake := robotfake.New(t)
+	fake.PendingPolls = 1 << 30
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	timeout, diags := types.ObjectValue(testFirewallTimeouts.AttributeTypes(context.Background()), map[string]attr.Value{
+		"create": types.StringValue("50ms"),
+		"update": types.StringNull(),
+		"delete": types.StringNull(),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	_, diags = r.create(firewallResourceModel{
+		ServerIP:     types.StringValue("192.0.2.10"),
+		Active:       types.BoolValue(true),
+		WhitelistHOS: types.BoolValue(true),
+		Rule:         []firewallRuleModel{{Action: types.StringValue("accept")}},
+		Timeouts:     timeouts.Value{Object: timeout},
+	})
+	if !diags.HasError() || !strings.Contains(diags[0].Detail(), "in process") {
+		t.Errorf("expected create to time out waiting for the firewall, got %v", diags)
+	}
+}
+
+func TestResourceFirewallDelete(t *testing.T) {
+	for _, tc := range []struct {
+		onDestroy string
+		status    string
+		rules     int
+	}{
+		{onDestroy: "clear", status: "active", rules: 0},
+		{onDestroy: "disable", status: "disabled", rules: 1},
+		{onDestroy: "keep", status: "active", rules: 1},
+	} {
+		fake := robotfake.New(t)
+		fake.AddServer(321, "192.0.2.10", "fw")
+		r := newTestResource(t, newFirewallResource(), fake)
+
+		state, diags := r.create(firewallResourceModel{
+			Timeouts:     testFirewallTimeouts,
+			ServerIP:     types.StringValue("192.0.2.10"),
+			Active:       types.BoolValue(true),
+			WhitelistHOS: types.BoolValue(true),
+			Rule:         []firewallRuleModel{{Name: types.StringValue("Allow ssh"), DstPort: types.StringValue("22"), Protocol: types.StringValue("tcp"), Action: types.StringValue("accept")}},
+			OnDestroy:    types.StringValue(tc.onDestroy),
+		})
+		if diags.HasError() {
+			t.Fatalf("%s: create: %v", tc.onDestroy, diags)
+		}
+		if diags = r.delete(state); diags.HasError() {
+			t.Fatalf("%s: delete: %v", tc.onDestroy, diags)
+		}
+
+		firewall, err := testClient(fake).GetFirewall(context.Background(), "192.0.2.10")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if firewall.Status != tc.status || len(firewall.Rules.Input) != tc.rules {
+			t.Errorf("%s: expected status %s with %d rules, got %+v", tc.onDestroy, tc.status, tc.rules, firewall)
+		}
+	}
+}
+
+func TestResourceFirewallNamedRules(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	model := firewallResourceModel{
+		Timeouts:     testFirewallTimeouts,
+		ServerIP:     types.StringValue("192.0.2.10"),
+		Active:       types.BoolValue(true),
+		WhitelistHOS: types.BoolValue(true),
+		NamedRule: map[string]firewallNamedRuleModel{
+			"allow-grafana": testNamedRule(20, firewallNamedRuleModel{SrcIP: types.StringValue("198.51.100.0/24"), DstPort: types.StringValue("3000"), Protocol: types.StringValue("tcp")}),
+			"allow-ssh":     testNamedRule(10, firewallNamedRuleModel{DstPort: types.StringValue("22"), Protocol: types.StringValue("tcp")}),
+		},
+	}
+	state, diags := r.create(model)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	r.get(state, &model)
+	model.NamedRule["allow-icmp"] = testNamedRule(5, firewallNamedRuleModel{Protocol: types.StringValue("icmp")})
+	if state, diags = r.update(state, model); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	firewall, err := testClient(fake).GetFirewall(context.Background(), "192.0.2.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, rule := range firewall.Rules.Input {
+		names = append(names, rule.Name)
+	}
+	if strings.Join(names, ",") != "allow-icmp,allow-ssh,allow-grafana" || firewall.Rules.Input[2].IPVersion != "ipv4" {
+		t.Errorf("expected the rules to be sent by priority, got %+v", firewall.Rules.Input)
+	}
+
+	r.get(state, &model)
+	if len(model.Rule) != 0 || len(model.NamedRule) != 3 {
+		t.Fatalf("unexpected rules in state: %v %v", model.Rule, model.NamedRule)
+	}
+	for name, want := range map[string]int64{"allow-icmp": 5, "allow-ssh": 10, "allow-grafana": 20} {
+		if rule := model.NamedRule[name]; rule.Priority.ValueInt64() != want {
+			t.Errorf("expected %s to keep priority %d, got %v", name, want, rule)
+		}
+	}
+	if ipVersion := model.NamedRule["allow-grafana"].IPVersion.ValueString(); ipVersion != "ipv4" {
+		t.Errorf("expected the ip_version of allow-grafana to default to ipv4, got %q", ipVersion)
+	}
+
+	// rules sharing a name outside Terraform cannot be keyed by it and are kept as
+	// rule blocks instead of dropped
+	firewall.Rules.Input[1].Name = "allow-icmp"
+	if err := testClient(fake).SetFirewall(context.Background(), *firewall); err != nil {
+		t.Fatal(err)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r.get(state, &model)
+	if len(model.Rule) != 3 || len(model.NamedRule) != 0 {
+		t.Errorf("expected rules sharing a name to be read as rule blocks, got %v %v", model.Rule, model.NamedRule)
+	}
+}
+
+// TestResourceFirewallNamedRuleEdit makes sure editing a named rule plans an update of
+// that rule only, instead of removing and adding rules.
+func TestResourceFirewallNamedRuleEdit(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "fw")
+	r := newTestResource(t, newFirewallResource(), fake)
+
+	model := firewallResourceModel{
+		Timeouts:     testFirewallTimeouts,
+		ServerIP:     types.StringValue("192.0.2.10"),
+		Active:       types.BoolValue(true),
+		WhitelistHOS: types.BoolValue(true),
+		NamedRule: map[string]firewallNamedRuleModel{
+			"allow-https": testNamedRule(20, firewallNamedRuleModel{DstPort: types.StringValue("443"), Protocol: types.StringValue("tcp")}),
+			"allow-ssh":   testNamedRule(10, firewallNamedRuleModel{DstPort: types.StringValue("22"), Protocol: types.StringValue("tcp")}),
+		},
+	}
+	state, diags := r.create(model)
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	r.get(state, &model)
+	ssh := model.NamedRule["allow-ssh"]
+	ssh.DstPort = types.StringValue("2222")
+	model.NamedRule["allow-ssh"] = ssh
+	plan, diags := r.modifyPlan(state, model)
+	if diags.HasError() {
+		t.Fatalf("plan: %v", diags)
+	}
+
+	diffs, err := state.Raw.Diff(plan.Raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := tftypes.NewAttributePath().WithAttributeName("named_rule").WithElementKeyString("allow-ssh").WithAttributeName("dst_port")
+	var updated bool
+	for _, diff := range diffs {
+		steps := diff.Path.Steps()
+		if len(steps) < 2 || steps[0] != tftypes.AttributeName("named_rule") {
+			continue
+		}
+		if steps[1] != tftypes.ElementKeyString("allow-ssh") || diff.Value1 == nil || diff.Value2 == nil {
+			t.Errorf("expected only allow-ssh to change, got a diff at %s", diff.Path)
+		}
+		updated = updated || diff.Path.Equal(changed)
+	}
+	if !updated {
+		t.Errorf("expected an update of %s, got %v", changed, diffs)
+	}
+}
+
+// testNamedRule returns rule accepting traffic at priority.
+func testNamedRule(priority int64, rule firewallNamedRuleModel) firewallNamedRuleModel {
+	rule.Priority = types.Int64Value(priority)
+	rule.Action = types.StringValue("accept")
+	return rule
+}
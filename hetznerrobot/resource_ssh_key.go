@@ -3,150 +3,184 @@ package hetznerrobot
 import (
 	"context"
 	"errors"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"fmt"
 	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
-func resourceSshKey() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceSshKeyCreate,
-		ReadContext:   resourceSshKeyRead,
-		UpdateContext: resourceSshKeyUpdate,
-		DeleteContext: resourceSshKeyDelete,
+var sshKeyAPIFields = apiFieldMap(map[string]string{
+	"data": "data",
+	"name": "name",
+})
 
-		Importer: &schema.ResourceImporter{
-			StateContext: resourceSshKeyImportState,
-		},
+var sshKeyFingerprintPattern = regexp.MustCompile(`^[0-9a-f]{2}(:[0-9a-f]{2}){15}$`)
+
+type sshKeyResource struct {
+	client *robot.Client
+}
+
+type sshKeyResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Data        types.String `tfsdk:"data"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+	Type        types.String `tfsdk:"type"`
+	Size        types.Int64  `tfsdk:"size"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+}
+
+func newSshKeyResource() resource.Resource {
+	return &sshKeyResource{}
+}
+
+func (r *sshKeyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ssh_key"
+}
 
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:        schema.TypeString,
+func (r *sshKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Key name",
 			},
-			"data": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Key data in OpenSSH or SSH2 format",
-				ForceNew:    true,
+			"data": schema.StringAttribute{
+				Required:      true,
+				Description:   "Key data in OpenSSH or SSH2 format",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
 			},
-			"fingerprint": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Key fingerprint",
+			"fingerprint": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Key fingerprint",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"type": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Key algorithm type",
+			"type": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Key algorithm type",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"size": {
-				Type:        schema.TypeInt,
-				Computed:    true,
-				Description: "Key size in bits",
+			"size": schema.Int64Attribute{
+				Computed:      true,
+				Description:   "Key size in bits",
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
 			},
-			"created_at": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Creation date",
+			"created_at": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Creation date",
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
 		},
 	}
 }
 
-func resourceSshKeyImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	c := meta.(HetznerRobotClient)
-
-	keyFingerprint := d.Id()
-	match, err := regexp.Match(`^[0-9a-f]{2}(:[0-9a-f]{2}){15}$`, []byte(keyFingerprint))
-	if err != nil {
-		return nil, err
-	}
-	if !match {
-		return nil, errors.New("invalid key fingerprint format")
-	}
+func (r *sshKeyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = resourceClient(req, resp)
+}
 
-	key, err := c.getSshKey(ctx, keyFingerprint)
-	if err != nil {
-		return nil, err
+func (r *sshKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !sshKeyFingerprintPattern.MatchString(req.ID) {
+		resp.Diagnostics.AddError("Invalid key fingerprint format", fmt.Sprintf("expected an MD5 fingerprint like aa:bb:..., got %q", req.ID))
+		return
 	}
 
-	d.Set("name", key.Name)
-	d.Set("data", key.Data)
-	d.Set("fingerprint", key.Fingerprint)
-	d.Set("type", key.Type)
-	d.Set("size", key.Size)
-	d.Set("created_at", key.CreatedAt)
-
-	return []*schema.ResourceData{d}, nil
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func resourceSshKeyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
-
-	name := d.Get("name").(string)
-	data := d.Get("data").(string)
+func (r *sshKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan sshKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	key, err := c.createSshKey(ctx, name, data)
+	name := plan.Name.ValueString()
+	key, err := r.client.CreateSSHKey(ctx, name, plan.Data.ValueString())
 	if err != nil {
-		return diag.Errorf("Unable to create SSH key %q:\n\t %q", name, err)
+		resp.Diagnostics.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to create SSH key %q", name), err, sshKeyAPIFields)...)
+		return
 	}
 
-	d.Set("fingerprint", key.Fingerprint)
-	d.Set("type", key.Type)
-	d.Set("size", key.Size)
-	d.Set("created_at", key.CreatedAt)
-	d.SetId(key.Fingerprint)
+	plan.ID = types.StringValue(key.Fingerprint)
+	plan.Fingerprint = types.StringValue(key.Fingerprint)
+	plan.Type = types.StringValue(key.Type)
+	plan.Size = types.Int64Value(int64(key.Size))
+	plan.CreatedAt = types.StringValue(key.CreatedAt)
 
-	return diag.Diagnostics{}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceSshKeyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *sshKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state sshKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	keyFingerprint := d.Id()
+	keyFingerprint := state.ID.ValueString()
 
-	key, err := c.getSshKey(ctx, keyFingerprint)
+	key, err := r.client.GetSSHKey(ctx, keyFingerprint)
+	if errors.Is(err, robot.ErrNotFound) {
+		resp.Diagnostics.Append(removeGoneResource(ctx, &resp.State, "SSH key", keyFingerprint, err)...)
+		return
+	}
 	if err != nil {
-		return diag.Errorf("Unable to find SSH key %q:\n\t %q", keyFingerprint, err)
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to find SSH key %q", keyFingerprint), err.Error())
+		return
 	}
 
-	d.Set("name", key.Name)
-	d.Set("data", key.Data)
-	d.Set("fingerprint", key.Fingerprint)
-	d.Set("type", key.Type)
-	d.Set("size", key.Size)
-	d.Set("created_at", key.CreatedAt)
+	state.Name = types.StringValue(key.Name)
+	state.Data = types.StringValue(key.Data)
+	state.Fingerprint = types.StringValue(key.Fingerprint)
+	state.Type = types.StringValue(key.Type)
+	state.Size = types.Int64Value(int64(key.Size))
+	state.CreatedAt = types.StringValue(key.CreatedAt)
 
-	return diag.Diagnostics{}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
-func resourceSshKeyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *sshKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan sshKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	keyFingerprint := d.Id()
-	name := d.Get("name").(string)
+	keyFingerprint := plan.ID.ValueString()
 
-	key, err := c.updateSshKey(ctx, keyFingerprint, name)
+	key, err := r.client.UpdateSSHKey(ctx, keyFingerprint, plan.Name.ValueString())
 	if err != nil {
-		return diag.Errorf("Unable to update SSH key %q:\n\t %q", keyFingerprint, err)
+		resp.Diagnostics.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to update SSH key %q", keyFingerprint), err, sshKeyAPIFields)...)
+		return
 	}
 
-	d.Set("name", key.Name)
+	plan.Name = types.StringValue(key.Name)
 
-	return diag.Diagnostics{}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceSshKeyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *sshKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state sshKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	keyFingerprint := d.Id()
+	keyFingerprint := state.ID.ValueString()
 
-	err := c.deleteSshKey(ctx, keyFingerprint)
-	if err != nil {
-		return diag.Errorf("Unable to delete SSH key %q:\n\t %q", keyFingerprint, err)
+	err := r.client.DeleteSSHKey(ctx, keyFingerprint)
+	if err != nil && !errors.Is(err, robot.ErrNotFound) {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to delete SSH key %q", keyFingerprint), err.Error())
 	}
-
-	return diag.Diagnostics{}
 }
@@ -0,0 +1,116 @@
+package hetznerrobot
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+)
+
+const (
+	testSshKeyData        = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHS5pQGk7lk5rizDVjDMgOah7psFyfJ0+Gpb+Nk7BBxe test"
+	testSshKeyFingerprint = "59:af:34:3f:44:73:09:20:9d:ca:13:e9:7e:52:66:f0"
+)
+
+func TestAccResourceSshKey_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	fake := robotfake.New(t)
+
+	config := func(name string) string {
+		return fmt.Sprintf(`
+resource "hetzner-robot_ssh_key" "test" {
+  name = %q
+  data = %q
+}`, name, testSshKeyData)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t, fake),
+		Steps: []resource.TestStep{
+			{
+				Config: config("deploy"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "id", testSshKeyFingerprint),
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "type", "ED25519"),
+					resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "size", "256"),
+				),
+			},
+			{
+				Config: config("renamed"),
+				Check:  resource.TestCheckResourceAttr("hetzner-robot_ssh_key.test", "name", "renamed"),
+			},
+			{
+				ResourceName:      "hetzner-robot_ssh_key.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceSshKey(t *testing.T) {
+	fake := robotfake.New(t)
+	r := newTestResource(t, newSshKeyResource(), fake)
+
+	state, diags := r.create(sshKeyResourceModel{
+		Name: types.StringValue("deploy"),
+		Data: types.StringValue(testSshKeyData),
+	})
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	var model sshKeyResourceModel
+	r.get(state, &model)
+	if model.ID.ValueString() != testSshKeyFingerprint || model.Type.ValueString() != "ED25519" {
+		t.Errorf("unexpected key %s of type %s", model.ID, model.Type)
+	}
+
+	model.Name = types.StringValue("renamed")
+	if state, diags = r.update(state, model); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r.get(state, &model)
+	if model.Name.ValueString() != "renamed" {
+		t.Errorf("unexpected name %s", model.Name)
+	}
+
+	if diags := r.delete(state); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() || !state.Raw.IsNull() {
+		t.Errorf("expected a deleted key to be removed from state, got %v", diags)
+	}
+}
+
+func TestResourceSshKeyImport(t *testing.T) {
+	fake := robotfake.New(t)
+	r := newTestResource(t, newSshKeyResource(), fake)
+
+	if _, diags := r.importState("deploy"); !diags.HasError() {
+		t.Error("expected an import id which is no fingerprint to be rejected")
+	}
+	if _, diags := r.importState(testSshKeyFingerprint); diags.HasError() {
+		t.Errorf("import: %v", diags)
+	}
+}
+
+func TestResourceSshKeyInvalidData(t *testing.T) {
+	fake := robotfake.New(t)
+	r := newTestResource(t, newSshKeyResource(), fake)
+
+	_, diags := r.create(sshKeyResourceModel{
+		Name: types.StringValue("broken"),
+		Data: types.StringValue("ssh-ed25519 not-base64"),
+	})
+	if len(diags) != 1 || !diags[0].(diag.DiagnosticWithPath).Path().Equal(path.Root("data")) {
+		t.Errorf("expected a diagnostic pointing at data, got %v", diags)
+	}
+}
@@ -2,247 +2,350 @@ package hetznerrobot
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/robot"
 )
 
-func resourceVSwitch() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceVSwitchCreate,
-		ReadContext:   resourceVSwitchRead,
-		UpdateContext: resourceVSwitchUpdate,
-		DeleteContext: resourceVSwitchDelete,
+var vSwitchAPIFields = apiFieldMap(map[string]string{
+	"name":   "name",
+	"server": "servers",
+	"vlan":   "vlan",
+})
 
-		Importer: &schema.ResourceImporter{
-			StateContext: resourceVSwitchImportState,
-		},
+var vSwitchSubnetType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"ip":      types.StringType,
+	"mask":    types.Int64Type,
+	"gateway": types.StringType,
+}}
+
+var vSwitchCloudNetworkType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"id":      types.Int64Type,
+	"ip":      types.StringType,
+	"mask":    types.Int64Type,
+	"gateway": types.StringType,
+}}
+
+type vSwitchResource struct {
+	client *robot.Client
+}
+
+type vSwitchResourceModel struct {
+	ID            types.String         `tfsdk:"id"`
+	Name          types.String         `tfsdk:"name"`
+	Vlan          types.Int64          `tfsdk:"vlan"`
+	IsCancelled   types.Bool           `tfsdk:"is_cancelled"`
+	Servers       []vSwitchServerModel `tfsdk:"servers"`
+	Subnets       types.List           `tfsdk:"subnets"`
+	CloudNetworks types.List           `tfsdk:"cloud_networks"`
+}
+
+type vSwitchServerModel struct {
+	ServerNumber  types.Int64  `tfsdk:"server_number"`
+	ServerIP      types.String `tfsdk:"server_ip"`
+	ServerIPv6Net types.String `tfsdk:"server_ipv6_net"`
+	Status        types.String `tfsdk:"status"`
+}
+
+func newVSwitchResource() resource.Resource {
+	return &vSwitchResource{}
+}
 
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:        schema.TypeString,
+func (r *vSwitchResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vswitch"
+}
+
+func (r *vSwitchResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "vSwitch name",
 			},
-			"vlan": {
-				Type:        schema.TypeInt,
+			"vlan": schema.Int64Attribute{
 				Required:    true,
 				Description: "VLAN ID",
 			},
 			// computed / read-only fields
-			"is_cancelled": {
-				Type:        schema.TypeBool,
-				Computed:    true,
-				Description: "Cancellation status",
+			"is_cancelled": schema.BoolAttribute{
+				Computed:      true,
+				Description:   "Cancellation status",
+				PlanModifiers: []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
 			},
-			"servers": {
-				Type:        schema.TypeList,
+			"subnets": schema.ListAttribute{
+				Computed:      true,
+				Description:   "Attached subnet list",
+				ElementType:   vSwitchSubnetType,
+				PlanModifiers: []planmodifier.List{listplanmodifier.UseStateForUnknown()},
+			},
+			"cloud_networks": schema.ListAttribute{
+				Computed:      true,
+				Description:   "Attached cloud network list",
+				ElementType:   vSwitchCloudNetworkType,
+				PlanModifiers: []planmodifier.List{listplanmodifier.UseStateForUnknown()},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"servers": schema.ListNestedBlock{
 				Description: "Attached server list",
-				Optional:    true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"server_number": {
-							Type:     schema.TypeInt,
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"server_number": schema.Int64Attribute{
 							Required: true,
 						},
-						"server_ip": {
-							Type:     schema.TypeString,
+						"server_ip": schema.StringAttribute{
 							Computed: true,
 						},
-						"server_ipv6_net": {
-							Type:     schema.TypeString,
+						"server_ipv6_net": schema.StringAttribute{
 							Computed: true,
 						},
-						"status": {
-							Type:     schema.TypeString,
+						"status": schema.StringAttribute{
 							Computed: true,
 						},
 					},
 				},
 			},
-			"subnets": {
-				Type:        schema.TypeList,
-				Computed:    true,
-				Description: "Attached subnet list",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"ip": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"mask": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-						"gateway": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
-			},
-			"cloud_networks": {
-				Type:        schema.TypeList,
-				Computed:    true,
-				Description: "Attached cloud network list",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-						"ip": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-						"mask": {
-							Type:     schema.TypeInt,
-							Required: true,
-						},
-						"gateway": {
-							Type:     schema.TypeString,
-							Required: true,
-						},
-					},
-				},
-			},
 		},
 	}
 }
-func resourceVSwitchImportState(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	c := meta.(HetznerRobotClient)
 
-	vSwitchID := d.Id()
-	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err)
-	}
+func (r *vSwitchResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = resourceClient(req, resp)
+}
 
-	d.Set("name", vSwitch.Name)
-	d.Set("vlan", vSwitch.Vlan)
-	d.Set("is_cancelled", vSwitch.Cancelled)
-	d.Set("servers", vSwitch.Server)
-	d.Set("subnets", vSwitch.Subnet)
-	d.Set("cloud_networks", vSwitch.CloudNetwork)
+func (r *vSwitchResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if _, err := strconv.Atoi(req.ID); err != nil {
+		resp.Diagnostics.AddError("Invalid VSwitch ID", fmt.Sprintf("invalid VSwitch ID %q: %s", req.ID, err))
+		return
+	}
 
-	results := make([]*schema.ResourceData, 1)
-	results[0] = d
-	return results, nil
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func resourceVSwitchCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *vSwitchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan vSwitchResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	name := d.Get("name").(string)
-	vlan := d.Get("vlan").(int)
-	vSwitch, err := c.createVSwitch(ctx, name, vlan)
+	vSwitch, err := r.client.CreateVSwitch(ctx, plan.Name.ValueString(), int(plan.Vlan.ValueInt64()))
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("Unable to create VSwitch :\n\t %q", err))
+		resp.Diagnostics.Append(apiErrorDiagnostics("Unable to create VSwitch", err, vSwitchAPIFields)...)
+		return
 	}
+	plan.ID = types.StringValue(strconv.Itoa(vSwitch.ID))
 
-	d.Set("is_cancelled", vSwitch.Cancelled)
-	d.Set("servers", vSwitch.Server)
-	d.Set("subnets", vSwitch.Subnet)
-	d.Set("cloud_networks", vSwitch.CloudNetwork)
-	d.SetId(strconv.Itoa(vSwitch.ID))
-
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
+	var servers []robot.VSwitchServer
+	for _, server := range plan.Servers {
+		servers = append(servers, robot.VSwitchServer{ServerNumber: int(server.ServerNumber.ValueInt64())})
+	}
+	if len(servers) > 0 {
+		// The vSwitch exists from here on, keep it in state even if attaching fails.
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
+		if err := r.client.AddVSwitchServers(ctx, vSwitch.ID, servers); err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to add servers to VSwitch %d", vSwitch.ID), err, vSwitchAPIFields)...)
+			return
+		}
+		vSwitchID := vSwitch.ID
+		if vSwitch, err = r.client.GetVSwitch(ctx, vSwitchID); err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Unable to find VSwitch with ID %d", vSwitchID), err.Error())
+			return
+		}
+	}
 
-	return diags
+	resp.Diagnostics.Append(plan.set(vSwitch)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceVSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *vSwitchResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state vSwitchResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	vSwitchID := d.Id()
-	vSwitch, err := c.getVSwitch(ctx, vSwitchID)
+	vSwitchID, err := strconv.Atoi(state.ID.ValueString())
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err))
+		resp.Diagnostics.AddError("Invalid VSwitch ID", fmt.Sprintf("invalid VSwitch ID %q: %s", state.ID.ValueString(), err))
+		return
+	}
+	vSwitch, err := r.client.GetVSwitch(ctx, vSwitchID)
+	if errors.Is(err, robot.ErrNotFound) {
+		resp.Diagnostics.Append(removeGoneResource(ctx, &resp.State, "VSwitch", state.ID.ValueString(), err)...)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to find VSwitch with ID %d", vSwitchID), err.Error())
+		return
 	}
 
-	d.Set("name", vSwitch.Name)
-	d.Set("vlan", vSwitch.Vlan)
-	d.Set("cancelled", vSwitch.Cancelled)
-	d.Set("servers", vSwitch.Server)
-	d.Set("subnets", vSwitch.Subnet)
-	d.Set("cloud_networks", vSwitch.CloudNetwork)
-
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
-
-	return diags
+	state.Name = types.StringValue(vSwitch.Name)
+	state.Vlan = types.Int64Value(int64(vSwitch.Vlan))
+	resp.Diagnostics.Append(state.set(vSwitch)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
-func resourceVSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *vSwitchResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state vSwitchResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	vSwitchID := d.Id()
-	name := d.Get("name").(string)
-	vlan := d.Get("vlan").(int)
-	err := c.updateVSwitch(ctx, vSwitchID, name, vlan)
+	vSwitchID, err := strconv.Atoi(state.ID.ValueString())
 	if err != nil {
-		return diag.Errorf("Unable to update VSwitch:\n\t %q", err)
+		resp.Diagnostics.AddError("Invalid VSwitch ID", fmt.Sprintf("invalid VSwitch ID %q: %s", state.ID.ValueString(), err))
+		return
+	}
+	err = r.client.UpdateVSwitch(ctx, vSwitchID, plan.Name.ValueString(), int(plan.Vlan.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to update VSwitch %d", vSwitchID), err, vSwitchAPIFields)...)
+		return
 	}
 
-	if d.HasChange("servers") {
-		o, n := d.GetChange("servers")
-
-		oldServers := o.([]interface{})
-		newServers := n.([]interface{})
+	planned := make(map[int64]struct{}, len(plan.Servers))
+	for _, server := range plan.Servers {
+		planned[server.ServerNumber.ValueInt64()] = struct{}{}
+	}
+	attached := make(map[int64]struct{}, len(state.Servers))
+	for _, server := range state.Servers {
+		attached[server.ServerNumber.ValueInt64()] = struct{}{}
+	}
 
-		mb := make(map[int]struct{}, len(newServers))
-		for _, x := range newServers {
-			srv := x.(map[string]interface{})
-			mb[srv["server_number"].(int)] = struct{}{}
-		}
-		var serversToRemove []HetznerRobotVSwitchServer
-		for _, x := range oldServers {
-			srv := x.(map[string]interface{})
-			srvNum := srv["server_number"].(int)
-			if _, found := mb[srvNum]; !found {
-				serversToRemove = append(serversToRemove, HetznerRobotVSwitchServer{ServerNumber: srvNum})
-			}
+	var serversToRemove []robot.VSwitchServer
+	for _, server := range state.Servers {
+		if _, found := planned[server.ServerNumber.ValueInt64()]; !found {
+			serversToRemove = append(serversToRemove, robot.VSwitchServer{ServerNumber: int(server.ServerNumber.ValueInt64())})
 		}
-
-		if err := c.removeVSwitchServers(ctx, vSwitchID, serversToRemove); err != nil {
-			diag.Errorf("Unable to remove servers from VSwitch:\n\t %q", err)
+	}
+	if len(serversToRemove) > 0 {
+		if err := r.client.RemoveVSwitchServers(ctx, vSwitchID, serversToRemove); err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to remove servers from VSwitch %d", vSwitchID), err, vSwitchAPIFields)...)
+			return
 		}
+	}
 
-		ma := make(map[int]struct{}, len(oldServers))
-		for _, x := range oldServers {
-			srv := x.(map[string]interface{})
-			ma[srv["server_number"].(int)] = struct{}{}
+	var serversToAdd []robot.VSwitchServer
+	for _, server := range plan.Servers {
+		if _, found := attached[server.ServerNumber.ValueInt64()]; !found {
+			serversToAdd = append(serversToAdd, robot.VSwitchServer{ServerNumber: int(server.ServerNumber.ValueInt64())})
 		}
-		var serversToAdd []HetznerRobotVSwitchServer
-		for _, x := range newServers {
-			srv := x.(map[string]interface{})
-			srvNum := srv["server_number"].(int)
-			if _, found := ma[srvNum]; !found {
-				serversToAdd = append(serversToAdd, HetznerRobotVSwitchServer{ServerNumber: srvNum})
-			}
+	}
+	if len(serversToAdd) > 0 {
+		if err := r.client.AddVSwitchServers(ctx, vSwitchID, serversToAdd); err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostics(fmt.Sprintf("Unable to add servers to VSwitch %d", vSwitchID), err, vSwitchAPIFields)...)
+			return
 		}
+	}
 
-		if err := c.addVSwitchServers(ctx, vSwitchID, serversToAdd); err != nil {
-			diag.Errorf("Unable to add servers to VSwitch:\n\t %q", err)
-		}
+	vSwitch, err := r.client.GetVSwitch(ctx, vSwitchID)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to find VSwitch with ID %d", vSwitchID), err.Error())
+		return
 	}
 
-	return resourceVSwitchRead(ctx, d, meta)
+	resp.Diagnostics.Append(plan.set(vSwitch)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceVSwitchDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(HetznerRobotClient)
+func (r *vSwitchResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state vSwitchResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	vSwitchID := d.Id()
-	err := c.deleteVSwitch(ctx, vSwitchID)
+	vSwitchID, err := strconv.Atoi(state.ID.ValueString())
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("Unable to find VSwitch with ID %s:\n\t %q", vSwitchID, err))
+		resp.Diagnostics.AddError("Invalid VSwitch ID", fmt.Sprintf("invalid VSwitch ID %q: %s", state.ID.ValueString(), err))
+		return
 	}
+	err = r.client.DeleteVSwitch(ctx, vSwitchID)
+	if err != nil && !errors.Is(err, robot.ErrNotFound) {
+		resp.Diagnostics.AddError(fmt.Sprintf("Unable to delete VSwitch with ID %d", vSwitchID), err.Error())
+	}
+}
 
-	// Warning or errors can be collected in a slice type
+// set copies the computed attributes of vSwitch into the model. Servers keep the
+// order of the model, Robot does not return them in the order they were attached.
+func (m *vSwitchResourceModel) set(vSwitch *robot.VSwitch) diag.Diagnostics {
 	var diags diag.Diagnostics
 
+	m.IsCancelled = types.BoolValue(vSwitch.Cancelled)
+
+	byNumber := make(map[int64]robot.VSwitchServer, len(vSwitch.Server))
+	for _, server := range vSwitch.Server {
+		byNumber[int64(server.ServerNumber)] = server
+	}
+	servers := make([]vSwitchServerModel, 0, len(vSwitch.Server))
+	for _, server := range m.Servers {
+		if attached, found := byNumber[server.ServerNumber.ValueInt64()]; found {
+			servers = append(servers, flattenVSwitchServerModel(attached))
+			delete(byNumber, server.ServerNumber.ValueInt64())
+		}
+	}
+	for _, server := range vSwitch.Server {
+		if _, remaining := byNumber[int64(server.ServerNumber)]; remaining {
+			servers = append(servers, flattenVSwitchServerModel(server))
+		}
+	}
+	m.Servers = servers
+
+	subnets := make([]attr.Value, 0, len(vSwitch.Subnet))
+	for _, subnet := range vSwitch.Subnet {
+		value, d := types.ObjectValue(vSwitchSubnetType.AttrTypes, map[string]attr.Value{
+			"ip":      types.StringValue(subnet.IP),
+			"mask":    types.Int64Value(int64(subnet.Mask)),
+			"gateway": types.StringValue(subnet.Gateway),
+		})
+		diags.Append(d...)
+		subnets = append(subnets, value)
+	}
+	list, d := types.ListValue(vSwitchSubnetType, subnets)
+	diags.Append(d...)
+	m.Subnets = list
+
+	cloudNetworks := make([]attr.Value, 0, len(vSwitch.CloudNetwork))
+	for _, cloudNetwork := range vSwitch.CloudNetwork {
+		value, d := types.ObjectValue(vSwitchCloudNetworkType.AttrTypes, map[string]attr.Value{
+			"id":      types.Int64Value(int64(cloudNetwork.ID)),
+			"ip":      types.StringValue(cloudNetwork.IP),
+			"mask":    types.Int64Value(int64(cloudNetwork.Mask)),
+			"gateway": types.StringValue(cloudNetwork.Gateway),
+		})
+		diags.Append(d...)
+		cloudNetworks = append(cloudNetworks, value)
+	}
+	list, d = types.ListValue(vSwitchCloudNetworkType, cloudNetworks)
+	diags.Append(d...)
+	m.CloudNetworks = list
+
 	return diags
 }
+
+func flattenVSwitchServerModel(server robot.VSwitchServer) vSwitchServerModel {
+	return vSwitchServerModel{
+		ServerNumber:  types.Int64Value(int64(server.ServerNumber)),
+		ServerIP:      types.StringValue(server.ServerIP),
+		ServerIPv6Net: types.StringValue(server.ServerIPv6Net),
+		Status:        types.StringValue(server.Status),
+	}
+}
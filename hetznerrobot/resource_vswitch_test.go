@@ -0,0 +1,103 @@
+package hetznerrobot
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+)
+
+func TestAccResourceVSwitch_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "one")
+	fake.AddServer(322, "192.0.2.11", "two")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories(t, fake),
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "hetzner-robot_vswitch" "test" {
+  name = "private"
+  vlan = 4010
+
+  servers {
+    server_number = 321
+  }
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "servers.#", "1"),
+					resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "servers.0.server_ip", "192.0.2.10"),
+				),
+			},
+			{
+				Config: `
+resource "hetzner-robot_vswitch" "test" {
+  name = "private"
+  vlan = 4010
+
+  servers {
+    server_number = 321
+  }
+  servers {
+    server_number = 322
+  }
+}`,
+				Check: resource.TestCheckResourceAttr("hetzner-robot_vswitch.test", "servers.#", "2"),
+			},
+		},
+	})
+}
+
+func TestResourceVSwitch(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "one")
+	fake.AddServer(322, "192.0.2.11", "two")
+	fake.AddServer(323, "192.0.2.12", "three")
+	r := newTestResource(t, newVSwitchResource(), fake)
+
+	state, diags := r.create(vSwitchResourceModel{
+		Name:          types.StringValue("private"),
+		Vlan:          types.Int64Value(4010),
+		Servers:       []vSwitchServerModel{{ServerNumber: types.Int64Value(321)}},
+		Subnets:       types.ListUnknown(vSwitchSubnetType),
+		CloudNetworks: types.ListUnknown(vSwitchCloudNetworkType),
+	})
+	if diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	var model vSwitchResourceModel
+	r.get(state, &model)
+	if model.ID.ValueString() == "" || model.Servers[0].ServerIP.ValueString() != "192.0.2.10" || model.IsCancelled.ValueBool() {
+		t.Errorf("unexpected state after create: id %s, servers %v", model.ID, model.Servers)
+	}
+
+	// servers keep the configured order
+	model.Servers = []vSwitchServerModel{{ServerNumber: types.Int64Value(323)}, {ServerNumber: types.Int64Value(322)}}
+	if state, diags = r.update(state, model); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+	r.get(state, &model)
+	if len(model.Servers) != 2 || model.Servers[0].ServerNumber.ValueInt64() != 323 || model.Servers[1].ServerIP.ValueString() != "192.0.2.11" {
+		t.Errorf("unexpected servers after update: %v", model.Servers)
+	}
+
+	if diags := r.delete(state); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+	if state, diags = r.read(state); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+	r.get(state, &model)
+	if !model.IsCancelled.ValueBool() {
+		t.Error("expected the vSwitch to be cancelled")
+	}
+
+	model.ID = types.StringValue("1")
+	if state, diags = r.read(r.state(model)); diags.HasError() || !state.Raw.IsNull() {
+		t.Errorf("expected an unknown vSwitch to be removed from state, got %v", diags)
+	}
+}
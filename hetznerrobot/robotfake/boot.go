@@ -0,0 +1,266 @@
+package robotfake
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+var (
+	rescueOperatingSystems = []string{"linux", "vkvm"}
+	linuxDistributions     = []string{"Debian 12 base", "Ubuntu 24.04 LTS base", "Rocky Linux 9 base"}
+	linuxLanguages         = []string{"en", "de"}
+)
+
+// installer describes the options of a boot profile other than rescue.
+type installer struct {
+	dists        []string
+	langs        []string
+	distOptional bool // the first dist is used without one
+	hostname     bool
+}
+
+var installers = map[string]installer{
+	"linux":   {dists: linuxDistributions, langs: linuxLanguages},
+	"vnc":     {dists: []string{"Debian 12", "Ubuntu 24.04"}, langs: linuxLanguages},
+	"windows": {dists: []string{"standard", "datacenter"}, langs: linuxLanguages, distOptional: true},
+	"plesk":   {dists: []string{"Debian 12 Plesk"}, langs: linuxLanguages, hostname: true},
+	"cpanel":  {dists: []string{"AlmaLinux 9 cPanel"}, langs: linuxLanguages, hostname: true},
+}
+
+var bootProfiles = []string{"rescue", "linux", "vnc", "windows", "plesk", "cpanel"}
+
+type boot struct {
+	Profile        string // "" when no profile is armed
+	OS             string
+	Lang           string
+	Hostname       string
+	Arch           int
+	Keyboard       string
+	Password       string
+	AuthorizedKeys []key
+	HostKeys       []key
+}
+
+var (
+	architectures   = []string{"64", "32"}
+	keyboardLayouts = []string{"us", "de", "fr", "uk"}
+)
+
+func (s *Server) handleBoot(w http.ResponseWriter, r *http.Request, segments []string, form map[string][]string) {
+	if len(segments) == 0 {
+		writeNotFound(w, "NOT_FOUND")
+		return
+	}
+
+	srv := s.lookupServer(segments[0])
+	if srv == nil {
+		writeNotFound(w, "SERVER_NOT_FOUND")
+		return
+	}
+	b := s.boots[srv.Number]
+
+	if len(segments) == 1 {
+		if r.Method != http.MethodGet {
+			writeMethodNotAllowed(w)
+			return
+		}
+		profiles := make(map[string]interface{}, len(bootProfiles))
+		for _, profile := range bootProfiles {
+			profiles[profile] = b.profileJSON(srv, profile)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"boot": profiles})
+		return
+	}
+
+	profile := segments[1]
+	if !contains(bootProfiles, profile) {
+		writeNotFound(w, "NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{profile: b.profileJSON(srv, profile)})
+	case http.MethodPost:
+		if b.Profile != "" {
+			writeError(w, http.StatusConflict, "BOOT_ALREADY_ENABLED", fmt.Sprintf("The boot configuration %s is already active", b.Profile), nil, nil)
+			return
+		}
+
+		var authorizedKeys []key
+		if profile == "rescue" || profile == "linux" {
+			for _, fingerprint := range form["authorized_key"] {
+				k, ok := s.keys[fingerprint]
+				if !ok {
+					writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, []string{"authorized_key"})
+					return
+				}
+				authorizedKeys = append(authorizedKeys, *k)
+			}
+		}
+		arch := first(form["arch"])
+		if arch != "" && (profile == "windows" || !contains(architectures, arch)) {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, []string{"arch"})
+			return
+		}
+		keyboard := first(form["keyboard"])
+		if keyboard != "" && (profile != "rescue" || !contains(keyboardLayouts, keyboard)) {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, []string{"keyboard"})
+			return
+		}
+
+		switch profile {
+		case "rescue":
+			os := first(form["os"])
+			if os == "" {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", []string{"os"}, nil)
+				return
+			}
+			if !contains(rescueOperatingSystems, os) {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, []string{"os"})
+				return
+			}
+			b.OS = os
+		default:
+			options := installers[profile]
+			dist, lang, hostname := first(form["dist"]), first(form["lang"]), first(form["hostname"])
+			if dist == "" && options.distOptional {
+				dist = options.dists[0]
+			}
+			var missing, invalid []string
+			if dist == "" {
+				missing = append(missing, "dist")
+			} else if !contains(options.dists, dist) {
+				invalid = append(invalid, "dist")
+			}
+			if lang == "" {
+				missing = append(missing, "lang")
+			} else if !contains(options.langs, lang) {
+				invalid = append(invalid, "lang")
+			}
+			if options.hostname && hostname == "" {
+				missing = append(missing, "hostname")
+			}
+			if len(missing) > 0 || len(invalid) > 0 {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", missing, invalid)
+				return
+			}
+			b.OS, b.Lang, b.Hostname = dist, lang, hostname
+		}
+
+		b.Profile = profile
+		b.Password = "pw" + strconv.Itoa(srv.Number)
+		b.AuthorizedKeys = authorizedKeys
+		b.Arch, _ = strconv.Atoi(arch)
+		if b.Arch == 0 {
+			b.Arch = 64
+		}
+		b.Keyboard = ""
+		if profile == "rescue" {
+			b.Keyboard = keyboard
+			if b.Keyboard == "" {
+				b.Keyboard = "us"
+			}
+		}
+		b.HostKeys = srv.HostKeys
+		writeJSON(w, http.StatusOK, map[string]interface{}{profile: b.profileJSON(srv, profile)})
+	case http.MethodDelete:
+		if b.Profile == profile {
+			*b = boot{}
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{profile: b.profileJSON(srv, profile)})
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (b *boot) profileJSON(srv *server, profile string) map[string]interface{} {
+	active := b.Profile == profile
+	document := map[string]interface{}{
+		"server_ip":       srv.IP,
+		"server_ipv6_net": srv.IPv6Net,
+		"server_number":   srv.Number,
+		"active":          active,
+		"password":        nil,
+	}
+	if profile == "rescue" || profile == "linux" {
+		document["authorized_key"] = []interface{}{}
+		document["host_key"] = []interface{}{}
+	}
+
+	switch profile {
+	case "rescue":
+		document["os"] = rescueOperatingSystems
+		document["arch"] = []int{64, 32}
+		document["boot_time"] = nil
+		document["keyboard"] = keyboardLayouts
+		if active {
+			document["os"] = b.OS
+			document["arch"] = b.Arch
+			document["keyboard"] = b.Keyboard
+		}
+	default:
+		options := installers[profile]
+		document["dist"] = options.dists
+		document["lang"] = options.langs
+		if profile != "windows" {
+			document["arch"] = []int{64, 32}
+		}
+		if options.hostname {
+			document["hostname"] = nil
+		}
+		if active {
+			document["dist"] = b.OS
+			document["lang"] = b.Lang
+			if profile != "windows" {
+				document["arch"] = b.Arch
+			}
+			if options.hostname {
+				document["hostname"] = b.Hostname
+			}
+		}
+	}
+
+	if active {
+		document["password"] = b.Password
+	}
+	if active && document["authorized_key"] != nil {
+		document["authorized_key"] = bootKeysJSON(b.AuthorizedKeys, true)
+		document["host_key"] = bootKeysJSON(b.HostKeys, false)
+	}
+
+	return document
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func bootKeysJSON(keys []key, named bool) []interface{} {
+	result := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		document := map[string]interface{}{
+			"fingerprint": k.Fingerprint,
+			"type":        k.Type,
+			"size":        k.Size,
+		}
+		if named {
+			document["name"] = k.Name
+		}
+		result = append(result, map[string]interface{}{"key": document})
+	}
+	return result
+}
@@ -0,0 +1,214 @@
+package robotfake
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxFirewallRules is the number of rules Robot accepts per chain.
+const maxFirewallRules = 10
+
+var firewallRuleFields = []string{"ip_version", "name", "dst_ip", "src_ip", "dst_port", "src_port", "protocol", "tcp_flags", "action"}
+
+type firewall struct {
+	Status       string // "active" / "disabled"
+	WhitelistHOS bool
+	FilterIPv6   bool
+	Input        []map[string]string
+	Output       []map[string]string
+
+	pendingPolls int
+}
+
+func (s *Server) handleFirewall(w http.ResponseWriter, r *http.Request, segments []string, form map[string][]string) {
+	if len(segments) == 0 {
+		writeNotFound(w, "NOT_FOUND")
+		return
+	}
+
+	if segments[0] == "template" {
+		s.handleFirewallTemplate(w, r, segments[1:], form)
+		return
+	}
+
+	srv := s.lookupServer(segments[0])
+	if srv == nil {
+		writeNotFound(w, "SERVER_NOT_FOUND")
+		return
+	}
+	fw, ok := s.firewalls[srv.Number]
+	if !ok {
+		writeNotFound(w, "FIREWALL_NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, fw.json(srv))
+		if fw.pendingPolls > 0 {
+			fw.pendingPolls--
+		}
+	case http.MethodPost:
+		if fw.pendingPolls > 0 {
+			writeError(w, http.StatusConflict, "FIREWALL_IN_PROCESS", "The firewall cannot be updated because it is currently in process", nil, nil)
+			return
+		}
+
+		status := first(form["status"])
+		if status != "active" && status != "disabled" {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, []string{"status"})
+			return
+		}
+
+		if templateID := first(form["template_id"]); templateID != "" {
+			id, _ := strconv.Atoi(templateID)
+			template, ok := s.templates[id]
+			if !ok {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, []string{"template_id"})
+				return
+			}
+			fw.WhitelistHOS = template.WhitelistHOS
+			fw.FilterIPv6 = template.FilterIPv6
+			fw.Input = template.Input
+			fw.Output = template.Output
+		} else {
+			rules, invalid := parseFirewallRules(form, "input")
+			output, invalidOutput := parseFirewallRules(form, "output")
+			invalid = append(invalid, invalidOutput...)
+			if len(invalid) > 0 {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, invalid)
+				return
+			}
+
+			fw.WhitelistHOS = first(form["whitelist_hos"]) == "true"
+			fw.FilterIPv6 = first(form["filter_ipv6"]) == "true"
+			fw.Input = rules
+			fw.Output = output
+		}
+		fw.Status = status
+		fw.pendingPolls = s.PendingPolls
+		writeJSON(w, http.StatusOK, fw.json(srv))
+	case http.MethodDelete:
+		if fw.pendingPolls > 0 {
+			writeError(w, http.StatusConflict, "FIREWALL_IN_PROCESS", "The firewall cannot be updated because it is currently in process", nil, nil)
+			return
+		}
+
+		fw.Input = nil
+		fw.Output = nil
+		fw.pendingPolls = s.PendingPolls
+		writeJSON(w, http.StatusOK, fw.json(srv))
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+// parseFirewallRules collects rules[<chain>][N][field] form values, validating them
+// the way Robot does.
+func parseFirewallRules(form map[string][]string, chain string) ([]map[string]string, []string) {
+	rules := make([]map[string]string, 0)
+	var invalid []string
+
+	for idx := 0; ; idx++ {
+		prefix := fmt.Sprintf("rules[%s][%d]", chain, idx)
+		rule := make(map[string]string)
+		for _, field := range firewallRuleFields {
+			if value := first(form[fmt.Sprintf("%s[%s]", prefix, field)]); value != "" {
+				rule[field] = value
+			}
+		}
+		if len(rule) == 0 {
+			break
+		}
+
+		if idx >= maxFirewallRules {
+			invalid = append(invalid, prefix)
+		}
+		if rule["action"] != "accept" && rule["action"] != "discard" {
+			invalid = append(invalid, prefix+"[action]")
+		}
+		ipVersion, ok := rule["ip_version"]
+		if ok && ipVersion != "ipv4" && ipVersion != "ipv6" {
+			invalid = append(invalid, prefix+"[ip_version]")
+		}
+		// addresses are only accepted for rules limited to their family
+		for _, field := range []string{"src_ip", "dst_ip"} {
+			if value, ok := rule[field]; ok && !validNet(value, ipVersion) {
+				invalid = append(invalid, fmt.Sprintf("%s[%s]", prefix, field))
+			}
+		}
+		for _, field := range []string{"src_port", "dst_port"} {
+			if value, ok := rule[field]; ok && !validPortRange(value) {
+				invalid = append(invalid, fmt.Sprintf("%s[%s]", prefix, field))
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, invalid
+}
+
+func validNet(value string, ipVersion string) bool {
+	ip := net.ParseIP(value)
+	if strings.Contains(value, "/") {
+		var err error
+		if ip, _, err = net.ParseCIDR(value); err != nil {
+			return false
+		}
+	}
+	switch {
+	case ip == nil:
+		return false
+	case ip.To4() != nil:
+		return ipVersion == "ipv4"
+	default:
+		return ipVersion == "ipv6"
+	}
+}
+
+func validPortRange(value string) bool {
+	for _, port := range strings.SplitN(value, "-", 2) {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 0 || n > 65535 {
+			return false
+		}
+	}
+	return true
+}
+
+func (fw *firewall) json(srv *server) map[string]interface{} {
+	status := fw.Status
+	if fw.pendingPolls > 0 {
+		status = "in process"
+	}
+
+	return map[string]interface{}{
+		"firewall": map[string]interface{}{
+			"server_ip":     srv.IP,
+			"server_number": srv.Number,
+			"status":        status,
+			"filter_ipv6":   fw.FilterIPv6,
+			"whitelist_hos": fw.WhitelistHOS,
+			"port":          "main",
+			"rules": map[string]interface{}{
+				"input":  firewallRulesJSON(fw.Input),
+				"output": firewallRulesJSON(fw.Output),
+			},
+		},
+	}
+}
+
+func firewallRulesJSON(rules []map[string]string) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		r := make(map[string]interface{}, len(firewallRuleFields))
+		for _, field := range firewallRuleFields {
+			r[field] = nullable(rule[field])
+		}
+		result = append(result, r)
+	}
+	return result
+}
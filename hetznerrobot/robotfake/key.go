@@ -0,0 +1,144 @@
+package robotfake
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var keyTypes = map[string]struct {
+	name string
+	size int
+}{
+	"ssh-ed25519":         {"ED25519", 256},
+	"ssh-rsa":             {"RSA", 4096},
+	"ecdsa-sha2-nistp256": {"ECDSA", 256},
+}
+
+type key struct {
+	Name        string
+	Fingerprint string
+	Type        string
+	Size        int
+	Data        string
+	CreatedAt   string
+}
+
+func (k *key) json() map[string]interface{} {
+	return map[string]interface{}{
+		"key": map[string]interface{}{
+			"name":        k.Name,
+			"fingerprint": k.Fingerprint,
+			"type":        k.Type,
+			"size":        k.Size,
+			"data":        k.Data,
+			"created_at":  k.CreatedAt,
+		},
+	}
+}
+
+// Fingerprint returns the MD5 fingerprint Robot identifies an OpenSSH public key by.
+func Fingerprint(data string) (string, error) {
+	fields := strings.Fields(data)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("invalid key data")
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return "", err
+	}
+
+	sum := md5.Sum(blob)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(hex, ":"), nil
+}
+
+func (s *Server) handleKey(w http.ResponseWriter, r *http.Request, segments []string, form map[string][]string) {
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			keys := make([]map[string]interface{}, 0, len(s.keys))
+			for _, fingerprint := range sortedKeys(s.keys) {
+				keys = append(keys, s.keys[fingerprint].json())
+			}
+			if len(keys) == 0 {
+				writeNotFound(w, "NOT_FOUND")
+				return
+			}
+			writeJSON(w, http.StatusOK, keys)
+		case http.MethodPost:
+			s.createKey(w, form)
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	k, ok := s.keys[segments[0]]
+	if !ok {
+		writeNotFound(w, "NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, k.json())
+	case http.MethodPost, http.MethodPut:
+		name := first(form["name"])
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", []string{"name"}, nil)
+			return
+		}
+		k.Name = name
+		writeJSON(w, http.StatusOK, k.json())
+	case http.MethodDelete:
+		delete(s.keys, k.Fingerprint)
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) createKey(w http.ResponseWriter, form map[string][]string) {
+	name, data := first(form["name"]), strings.TrimSpace(first(form["data"]))
+
+	var missing []string
+	if name == "" {
+		missing = append(missing, "name")
+	}
+	if data == "" {
+		missing = append(missing, "data")
+	}
+	if len(missing) > 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", missing, nil)
+		return
+	}
+
+	keyType, ok := keyTypes[strings.Fields(data)[0]]
+	fingerprint, err := Fingerprint(data)
+	if !ok || err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, []string{"data"})
+		return
+	}
+	if _, exists := s.keys[fingerprint]; exists {
+		writeError(w, http.StatusConflict, "KEY_ALREADY_EXISTS", "The supplied key already exists", nil, nil)
+		return
+	}
+
+	k := &key{
+		Name:        name,
+		Fingerprint: fingerprint,
+		Type:        keyType.name,
+		Size:        keyType.size,
+		Data:        data,
+		CreatedAt:   time.Now().UTC().Format("2006-01-02 15:04:05"),
+	}
+	s.keys[fingerprint] = k
+	writeJSON(w, http.StatusCreated, k.json())
+}
@@ -0,0 +1,52 @@
+package robotfake
+
+import (
+	"net/http"
+)
+
+var resetTypes = []string{"sw", "hw", "man", "power", "power_long"}
+
+func (s *Server) handleReset(w http.ResponseWriter, r *http.Request, segments []string, form map[string][]string) {
+	if len(segments) == 0 || segments[0] == "" {
+		writeNotFound(w, "NOT_FOUND")
+		return
+	}
+
+	srv := s.lookupServer(segments[0])
+	if srv == nil {
+		writeNotFound(w, "SERVER_NOT_FOUND")
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	resetType := first(form["type"])
+	if !contains(resetTypes, resetType) {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, []string{"type"})
+		return
+	}
+
+	// the server boots the armed profile, which Robot disarms afterwards
+	srv.Resets = append(srv.Resets, resetType)
+	b := s.boots[srv.Number]
+	srv.Running = b.Profile
+	*b = boot{}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"reset": map[string]interface{}{
+			"server_ip":     srv.IP,
+			"server_number": srv.Number,
+			"type":          resetType,
+		},
+	})
+}
+
+// Resets returns the reset types the server was reset with, in order.
+func (s *Server) Resets(number int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.servers[number].Resets...)
+}
@@ -0,0 +1,226 @@
+// Package robotfake implements an in-memory stand-in of the Hetzner Robot webservice
+// (https://robot.your-server.de/doc/webservice/en.html) for tests.
+//
+// It covers the endpoints used by the provider, answers with the same JSON documents
+// and error envelopes as Robot, emulates the asynchronous "in process" states of
+// firewalls and vSwitches and can enforce per-endpoint request limits.
+package robotfake
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	Username = "robot"
+	Password = "secret"
+)
+
+// Server is a fake Robot webservice listening on a local httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	// PendingPolls is the number of GET requests for which a firewall or vSwitch
+	// server assignment reports "in process" / "processing" after a change.
+	PendingPolls int
+
+	mu        sync.Mutex
+	servers   map[int]*server
+	boots     map[int]*boot
+	firewalls map[int]*firewall
+	templates map[int]*firewallTemplate
+	keys      map[string]*key
+	vSwitches map[int]*vSwitch
+	nextID    int
+
+	rateLimits map[string]int
+	requests   map[string]int
+	failures   []failure
+	userAgent  string
+}
+
+type failure struct {
+	method string
+	path   string
+	status int
+	code   string
+}
+
+// New starts a fake webservice. It is closed automatically at the end of the test.
+func New(t interface{ Cleanup(func()) }) *Server {
+	s := &Server{
+		servers:    make(map[int]*server),
+		boots:      make(map[int]*boot),
+		firewalls:  make(map[int]*firewall),
+		templates:  make(map[int]*firewallTemplate),
+		keys:       make(map[string]*key),
+		vSwitches:  make(map[int]*vSwitch),
+		nextID:     4000,
+		rateLimits: make(map[string]int),
+		requests:   make(map[string]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// SetRateLimit makes every request to the endpoint family (e.g. "firewall") beyond
+// limit fail with RATE_LIMIT_EXCEEDED. 0 removes the limit.
+func (s *Server) SetRateLimit(family string, limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rateLimits[family] = limit
+	s.requests[family] = 0
+}
+
+// FailNext makes the next request matching method and path prefix fail with the
+// given status and error code.
+func (s *Server) FailNext(method string, path string, status int, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, failure{method: method, path: path, status: status, code: code})
+}
+
+// Requests returns the number of requests received for the endpoint family.
+func (s *Server) Requests(family string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[family]
+}
+
+// UserAgent returns the User-Agent of the last authenticated request.
+func (s *Server) UserAgent() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.userAgent
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok || username != Username || password != Password {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Unauthorized", nil, nil)
+		return
+	}
+
+	form, err := parseForm(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, nil)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	family := segments[0]
+	s.requests[family]++
+	s.userAgent = r.UserAgent()
+	if limit := s.rateLimits[family]; limit > 0 && s.requests[family] > limit {
+		writeJSON(w, http.StatusForbidden, map[string]interface{}{
+			"error": map[string]interface{}{
+				"status":      http.StatusForbidden,
+				"code":        "RATE_LIMIT_EXCEEDED",
+				"message":     "Rate limit exceeded",
+				"max_request": limit,
+				"interval":    3600,
+			},
+		})
+		return
+	}
+
+	for i, f := range s.failures {
+		if f.method == r.Method && strings.HasPrefix(r.URL.Path, f.path) {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			writeError(w, f.status, f.code, "injected failure", nil, nil)
+			return
+		}
+	}
+
+	switch family {
+	case "server":
+		s.handleServer(w, r, segments[1:])
+	case "boot":
+		s.handleBoot(w, r, segments[1:], form)
+	case "firewall":
+		s.handleFirewall(w, r, segments[1:], form)
+	case "key":
+		s.handleKey(w, r, segments[1:], form)
+	case "reset":
+		s.handleReset(w, r, segments[1:], form)
+	case "vswitch":
+		s.handleVSwitch(w, r, segments[1:], form)
+	default:
+		writeNotFound(w, "NOT_FOUND")
+	}
+}
+
+// parseForm reads form values from the body for every method, as Robot also accepts
+// them for DELETE requests.
+func parseForm(r *http.Request) (url.Values, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range r.URL.Query() {
+		form[key] = append(form[key], values...)
+	}
+	return form, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string, missing []string, invalid []string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"status":  status,
+			"code":    code,
+			"message": message,
+			"missing": missing,
+			"invalid": invalid,
+		},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, code string) {
+	writeError(w, http.StatusNotFound, code, strings.ToLower(strings.ReplaceAll(code, "_", " ")), nil, nil)
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "method not allowed", nil, nil)
+}
+
+func nullable(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
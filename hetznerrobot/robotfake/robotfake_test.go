@@ -0,0 +1,84 @@
+package robotfake
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func request(t *testing.T, s *Server, method string, path string, form url.Values) (int, map[string]interface{}) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(Username, Password)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var body map[string]interface{}
+	_ = json.NewDecoder(res.Body).Decode(&body)
+	return res.StatusCode, body
+}
+
+func errorCode(body map[string]interface{}) string {
+	if e, ok := body["error"].(map[string]interface{}); ok {
+		return e["code"].(string)
+	}
+	return ""
+}
+
+func TestFirewallTransitions(t *testing.T) {
+	s := New(t)
+	s.PendingPolls = 1
+	s.AddServer(1, "192.0.2.1", "one")
+
+	form := url.Values{
+		"status":                  {"active"},
+		"whitelist_hos":           {"true"},
+		"rules[input][0][action]": {"accept"},
+	}
+	if status, body := request(t, s, http.MethodPost, "/firewall/192.0.2.1", form); status != http.StatusOK {
+		t.Fatalf("unexpected status %d: %v", status, body)
+	}
+	if status, body := request(t, s, http.MethodPost, "/firewall/1", form); status != http.StatusConflict || errorCode(body) != "FIREWALL_IN_PROCESS" {
+		t.Fatalf("expected FIREWALL_IN_PROCESS, got %d: %v", status, body)
+	}
+
+	_, body := request(t, s, http.MethodGet, "/firewall/1", nil)
+	if status := body["firewall"].(map[string]interface{})["status"]; status != "in process" {
+		t.Errorf("expected the firewall to be in process, got %v", status)
+	}
+	_, body = request(t, s, http.MethodGet, "/firewall/1", nil)
+	if status := body["firewall"].(map[string]interface{})["status"]; status != "active" {
+		t.Errorf("expected the firewall to be active, got %v", status)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := New(t)
+	s.AddServer(1, "192.0.2.1", "one")
+
+	if status, body := request(t, s, http.MethodGet, "/server/2", nil); status != http.StatusNotFound || errorCode(body) != "SERVER_NOT_FOUND" {
+		t.Errorf("expected SERVER_NOT_FOUND, got %d: %v", status, body)
+	}
+
+	_, body := request(t, s, http.MethodPost, "/vswitch", url.Values{"name": {"test"}, "vlan": {"12"}})
+	if invalid := body["error"].(map[string]interface{})["invalid"].([]interface{}); len(invalid) != 1 || invalid[0] != "vlan" {
+		t.Errorf("expected vlan to be invalid, got %v", body)
+	}
+
+	s.SetRateLimit("server", 1)
+	request(t, s, http.MethodGet, "/server/1", nil)
+	if status, body := request(t, s, http.MethodGet, "/server/1", nil); status != http.StatusForbidden || errorCode(body) != "RATE_LIMIT_EXCEEDED" {
+		t.Errorf("expected RATE_LIMIT_EXCEEDED, got %d: %v", status, body)
+	}
+}
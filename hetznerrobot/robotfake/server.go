@@ -0,0 +1,140 @@
+package robotfake
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type server struct {
+	Number    int
+	IP        string
+	IPv6Net   string
+	Name      string
+	Product   string
+	DC        string
+	Cancelled bool
+	HostKeys  []key
+	Resets    []string
+	Running   string // boot profile the server was last reset into, "" for the installed system
+}
+
+// AddServer registers a dedicated server, together with its boot configuration and an
+// empty, disabled firewall.
+func (s *Server) AddServer(number int, ip string, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.servers[number] = &server{
+		Number:  number,
+		IP:      ip,
+		IPv6Net: fmt.Sprintf("2a01:4f8:111:%x::", number%0xffff),
+		Name:    name,
+		Product: "AX41-NVMe",
+		DC:      "FSN1-DC14",
+		HostKeys: []key{
+			{Type: "ED25519", Size: 256, Fingerprint: hostKeyFingerprint(number, 0)},
+			{Type: "RSA", Size: 3072, Fingerprint: hostKeyFingerprint(number, 1)},
+		},
+	}
+	s.boots[number] = &boot{}
+	s.firewalls[number] = &firewall{Status: "disabled", WhitelistHOS: true}
+}
+
+// SetHostKey replaces the host keys the rescue system and installer of the server
+// report with a single key, e.g. the one of a local SSH stand-in.
+func (s *Server) SetHostKey(number int, keyType string, size int, fingerprint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.servers[number].HostKeys = []key{{Type: keyType, Size: size, Fingerprint: fingerprint}}
+}
+
+// CancelServer makes the server and everything attached to it vanish from the
+// webservice, as happens after a cancellation took effect.
+func (s *Server) CancelServer(number int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.servers, number)
+	delete(s.boots, number)
+	delete(s.firewalls, number)
+}
+
+// lookupServer resolves a server by number or main IPv4 address.
+func (s *Server) lookupServer(id string) *server {
+	if number, err := strconv.Atoi(id); err == nil {
+		return s.servers[number]
+	}
+	for _, srv := range s.servers {
+		if srv.IP == id {
+			return srv
+		}
+	}
+	return nil
+}
+
+func (srv *server) json() map[string]interface{} {
+	return map[string]interface{}{
+		"server_ip":         srv.IP,
+		"server_ipv6_net":   srv.IPv6Net,
+		"server_number":     srv.Number,
+		"server_name":       srv.Name,
+		"product":           srv.Product,
+		"dc":                srv.DC,
+		"traffic":           "unlimited",
+		"status":            "ready",
+		"cancelled":         srv.Cancelled,
+		"paid_until":        "2030-01-31",
+		"ip":                []string{srv.IP},
+		"subnet":            []map[string]string{{"ip": srv.IPv6Net, "mask": "64"}},
+		"linked_storagebox": nil,
+		"reset":             true,
+		"rescue":            true,
+		"vnc":               true,
+		"windows":           true,
+		"plesk":             true,
+		"cpanel":            true,
+		"wol":               true,
+		"hot_swap":          false,
+	}
+}
+
+func (s *Server) handleServer(w http.ResponseWriter, r *http.Request, segments []string) {
+	if r.Method != http.MethodGet {
+		writeMethodNotAllowed(w)
+		return
+	}
+
+	if len(segments) == 0 || segments[0] == "" {
+		servers := make([]map[string]interface{}, 0, len(s.servers))
+		for _, number := range sortedKeys(s.servers) {
+			servers = append(servers, map[string]interface{}{"server": s.servers[number].json()})
+		}
+		if len(servers) == 0 {
+			writeNotFound(w, "SERVER_NOT_FOUND")
+			return
+		}
+		writeJSON(w, http.StatusOK, servers)
+		return
+	}
+
+	srv := s.lookupServer(segments[0])
+	if srv == nil {
+		writeNotFound(w, "SERVER_NOT_FOUND")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"server": srv.json()})
+}
+
+// hostKeyFingerprint derives a stable MD5-style fingerprint for a server's host key.
+func hostKeyFingerprint(number int, idx int) string {
+	sum := md5.Sum([]byte(fmt.Sprintf("host-key-%d-%d", number, idx)))
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
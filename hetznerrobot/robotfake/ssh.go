@@ -0,0 +1,77 @@
+package robotfake
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ServeSSH starts a stand-in for the SSH daemon of a server on a local port and
+// returns its address. Until the server is reset into the rescue or linux profile it
+// presents the host key of the installed system, afterwards the one Robot reports
+// for the profile.
+func (s *Server) ServeSSH(t interface {
+	Cleanup(func())
+	Fatal(args ...interface{})
+}, number int) string {
+	installed, booted := newHostKey(t), newHostKey(t)
+	s.SetHostKey(number, "ED25519", 256, ssh.FingerprintLegacyMD5(booted.PublicKey()))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			s.mu.Lock()
+			signer := installed
+			if srv := s.servers[number]; srv != nil && (srv.Running == "rescue" || srv.Running == "linux") {
+				signer = booted
+			}
+			s.mu.Unlock()
+
+			go serveSSHConn(conn, signer)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// serveSSHConn completes the key exchange and rejects everything afterwards.
+func serveSSHConn(conn net.Conn, signer ssh.Signer) {
+	defer conn.Close()
+
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	defer serverConn.Close()
+
+	go ssh.DiscardRequests(requests)
+	for channel := range channels {
+		_ = channel.Reject(ssh.Prohibited, "stand-in")
+	}
+}
+
+func newHostKey(t interface{ Fatal(args ...interface{}) }) ssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
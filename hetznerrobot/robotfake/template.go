@@ -0,0 +1,114 @@
+package robotfake
+
+import (
+	"net/http"
+	"strconv"
+)
+
+type firewallTemplate struct {
+	ID           int
+	Name         string
+	WhitelistHOS bool
+	FilterIPv6   bool
+	IsDefault    bool
+	Input        []map[string]string
+	Output       []map[string]string
+}
+
+func (t *firewallTemplate) json(withRules bool) map[string]interface{} {
+	template := map[string]interface{}{
+		"id":            t.ID,
+		"name":          t.Name,
+		"filter_ipv6":   t.FilterIPv6,
+		"whitelist_hos": t.WhitelistHOS,
+		"is_default":    t.IsDefault,
+	}
+	if withRules {
+		template["rules"] = map[string]interface{}{
+			"input":  firewallRulesJSON(t.Input),
+			"output": firewallRulesJSON(t.Output),
+		}
+	}
+	return map[string]interface{}{"firewall_template": template}
+}
+
+func (s *Server) handleFirewallTemplate(w http.ResponseWriter, r *http.Request, segments []string, form map[string][]string) {
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			templates := make([]map[string]interface{}, 0, len(s.templates))
+			for _, id := range sortedKeys(s.templates) {
+				templates = append(templates, s.templates[id].json(false))
+			}
+			if len(templates) == 0 {
+				writeNotFound(w, "NOT_FOUND")
+				return
+			}
+			writeJSON(w, http.StatusOK, templates)
+		case http.MethodPost:
+			s.nextID++
+			t := &firewallTemplate{ID: s.nextID}
+			if !s.setFirewallTemplate(w, t, form) {
+				s.nextID--
+				return
+			}
+			s.templates[t.ID] = t
+			writeJSON(w, http.StatusCreated, t.json(true))
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	id, _ := strconv.Atoi(segments[0])
+	t, ok := s.templates[id]
+	if !ok {
+		writeNotFound(w, "NOT_FOUND")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, t.json(true))
+	case http.MethodPost:
+		if s.setFirewallTemplate(w, t, form) {
+			writeJSON(w, http.StatusOK, t.json(true))
+		}
+	case http.MethodDelete:
+		delete(s.templates, t.ID)
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+// setFirewallTemplate validates form and copies it into t, writing the error
+// response when it is invalid.
+func (s *Server) setFirewallTemplate(w http.ResponseWriter, t *firewallTemplate, form map[string][]string) bool {
+	name := first(form["name"])
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", []string{"name"}, nil)
+		return false
+	}
+
+	input, invalid := parseFirewallRules(form, "input")
+	output, invalidOutput := parseFirewallRules(form, "output")
+	invalid = append(invalid, invalidOutput...)
+	if len(invalid) > 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, invalid)
+		return false
+	}
+
+	t.Name = name
+	t.WhitelistHOS = first(form["whitelist_hos"]) == "true"
+	t.FilterIPv6 = first(form["filter_ipv6"]) == "true"
+	t.IsDefault = first(form["is_default"]) == "true"
+	t.Input = input
+	t.Output = output
+	if t.IsDefault {
+		for _, other := range s.templates {
+			other.IsDefault = other.ID == t.ID
+		}
+	}
+	return true
+}
@@ -0,0 +1,194 @@
+package robotfake
+
+import (
+	"net/http"
+	"strconv"
+)
+
+type vSwitch struct {
+	ID        int
+	Name      string
+	Vlan      int
+	Cancelled bool
+	Servers   []*vSwitchServer
+}
+
+type vSwitchServer struct {
+	Number       int
+	pendingPolls int
+}
+
+func (v *vSwitch) json(s *Server) map[string]interface{} {
+	servers := make([]map[string]interface{}, 0, len(v.Servers))
+	for _, member := range v.Servers {
+		status := "ready"
+		if member.pendingPolls > 0 {
+			status = "processing"
+		}
+		entry := map[string]interface{}{
+			"server_number": member.Number,
+			"status":        status,
+		}
+		if srv, ok := s.servers[member.Number]; ok {
+			entry["server_ip"] = srv.IP
+			entry["server_ipv6_net"] = srv.IPv6Net
+		}
+		servers = append(servers, entry)
+	}
+
+	return map[string]interface{}{
+		"id":            v.ID,
+		"name":          v.Name,
+		"vlan":          v.Vlan,
+		"cancelled":     v.Cancelled,
+		"server":        servers,
+		"subnet":        []interface{}{},
+		"cloud_network": []interface{}{},
+	}
+}
+
+func (s *Server) handleVSwitch(w http.ResponseWriter, r *http.Request, segments []string, form map[string][]string) {
+	if len(segments) == 0 || segments[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			vSwitches := make([]map[string]interface{}, 0, len(s.vSwitches))
+			for _, id := range sortedKeys(s.vSwitches) {
+				v := s.vSwitches[id]
+				vSwitches = append(vSwitches, map[string]interface{}{"id": v.ID, "name": v.Name, "vlan": v.Vlan, "cancelled": v.Cancelled})
+			}
+			writeJSON(w, http.StatusOK, vSwitches)
+		case http.MethodPost:
+			name, vlan, missing, invalid := parseVSwitch(form)
+			if len(missing) > 0 || len(invalid) > 0 {
+				writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", missing, invalid)
+				return
+			}
+			for _, v := range s.vSwitches {
+				if v.Vlan == vlan && !v.Cancelled {
+					writeError(w, http.StatusConflict, "VSWITCH_VLAN_NOT_UNIQUE", "The vlan id is already in use", nil, nil)
+					return
+				}
+			}
+			s.nextID++
+			v := &vSwitch{ID: s.nextID, Name: name, Vlan: vlan, Servers: make([]*vSwitchServer, 0)}
+			s.vSwitches[v.ID] = v
+			writeJSON(w, http.StatusCreated, v.json(s))
+		default:
+			writeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	id, err := strconv.Atoi(segments[0])
+	v, ok := s.vSwitches[id]
+	if err != nil || !ok {
+		writeNotFound(w, "VSWITCH_NOT_FOUND")
+		return
+	}
+
+	if len(segments) > 1 && segments[1] == "server" {
+		s.handleVSwitchServers(w, r, v, form)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, v.json(s))
+		for _, member := range v.Servers {
+			if member.pendingPolls > 0 {
+				member.pendingPolls--
+			}
+		}
+	case http.MethodPost:
+		name, vlan, missing, invalid := parseVSwitch(form)
+		if len(missing) > 0 || len(invalid) > 0 {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", missing, invalid)
+			return
+		}
+		v.Name, v.Vlan = name, vlan
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if first(form["cancellation_date"]) == "" {
+			writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", []string{"cancellation_date"}, nil)
+			return
+		}
+		v.Cancelled = true
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleVSwitchServers(w http.ResponseWriter, r *http.Request, v *vSwitch, form map[string][]string) {
+	numbers := make([]int, 0, len(form["server"]))
+	var invalid []string
+	for _, value := range form["server"] {
+		srv := s.lookupServer(value)
+		if srv == nil {
+			invalid = append(invalid, "server")
+			continue
+		}
+		numbers = append(numbers, srv.Number)
+	}
+	if len(numbers) == 0 && len(invalid) == 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", []string{"server"}, nil)
+		return
+	}
+	if len(invalid) > 0 {
+		writeError(w, http.StatusBadRequest, "INVALID_INPUT", "invalid input", nil, invalid)
+		return
+	}
+
+	for _, member := range v.Servers {
+		if member.pendingPolls > 0 {
+			writeError(w, http.StatusConflict, "VSWITCH_IN_PROCESS", "There is an update running for this vSwitch", nil, nil)
+			return
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		for _, number := range numbers {
+			v.Servers = append(v.Servers, &vSwitchServer{Number: number, pendingPolls: s.PendingPolls})
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		remaining := make([]*vSwitchServer, 0, len(v.Servers))
+		for _, member := range v.Servers {
+			if !containsInt(numbers, member.Number) {
+				remaining = append(remaining, member)
+			}
+		}
+		v.Servers = remaining
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeMethodNotAllowed(w)
+	}
+}
+
+func parseVSwitch(form map[string][]string) (string, int, []string, []string) {
+	var missing, invalid []string
+
+	name := first(form["name"])
+	if name == "" {
+		missing = append(missing, "name")
+	}
+
+	vlan, err := strconv.Atoi(first(form["vlan"]))
+	if first(form["vlan"]) == "" {
+		missing = append(missing, "vlan")
+	} else if err != nil || vlan < 4000 || vlan > 4091 {
+		invalid = append(invalid, "vlan")
+	}
+
+	return name, vlan, missing, invalid
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package hetznerrobot
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+type transportConfig struct {
+	timeout            time.Duration
+	proxyURL           string
+	caCertFile         string
+	caCertPEM          string
+	insecureSkipVerify bool
+}
+
+// newHTTPClient builds the long-lived http.Client shared by every request of a
+// configured provider, so connections are kept alive between requests.
+func newHTTPClient(config transportConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.proxyURL != "" {
+		proxyURL, err := url.Parse(config.proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid http_proxy %q: %w", config.proxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.insecureSkipVerify,
+	}
+
+	if config.caCertFile != "" || config.caCertPEM != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if config.caCertFile != "" {
+			pem, err := os.ReadFile(config.caCertFile)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read ca_cert_file: %w", err)
+			}
+			if !rootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificate found in ca_cert_file %s", config.caCertFile)
+			}
+		}
+		if config.caCertPEM != "" && !rootCAs.AppendCertsFromPEM([]byte(config.caCertPEM)) {
+			return nil, fmt.Errorf("no certificate found in ca_cert_pem")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Timeout:   config.timeout,
+		Transport: transport,
+	}, nil
+}
@@ -0,0 +1,59 @@
+// Package redact holds the form and JSON keys whose values are secret, shared by the
+// debug logs of the robot client and the cassettes of the recorder.
+package redact
+
+import "strings"
+
+// Value replaces the values of masked keys.
+const Value = "***"
+
+// LogKeys are masked in debug logs by default: rescue and installation passwords, SSH
+// key material and the Authorization header.
+var LogKeys = []string{
+	"authorization",
+	"authorized_key",
+	"data",
+	"host_key",
+	"password",
+}
+
+// CassetteKeys are masked in cassettes. Cassettes have to replay the documents Robot
+// returns, so only secret leaf values are masked: passwords and the Authorization
+// header, plus the key data below KeyMaterialKeys.
+var CassetteKeys = []string{
+	"authorization",
+	"password",
+}
+
+// KeyMaterialKeys hold the authorized and host keys of boot profiles. Their "data"
+// values are masked in cassettes, while the "data" of /key, a public key, is kept.
+var KeyMaterialKeys = []string{
+	"authorized_key",
+	"host_key",
+}
+
+// KeySet returns keys and extra as a set of lower case keys.
+func KeySet(keys []string, extra ...string) map[string]bool {
+	set := make(map[string]bool, len(keys)+len(extra))
+	for _, key := range keys {
+		set[key] = true
+	}
+	for _, key := range extra {
+		set[strings.ToLower(key)] = true
+	}
+	return set
+}
+
+// Masked matches plain keys as well as the first and last segment of Robot form keys
+// like "authorized_key[]" or "rules[input][0][name]".
+func Masked(keys map[string]bool, key string) bool {
+	key = strings.ToLower(key)
+	if keys[key] {
+		return true
+	}
+	if idx := strings.Index(key, "["); idx >= 0 {
+		segments := strings.Split(strings.TrimSuffix(key, "]"), "[")
+		return keys[segments[0]] || keys[segments[len(segments)-1]]
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#boot-configuration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/tidwall/gjson"
+)
+
+// BootProfiles are the profiles a server can be booted into, the rescue system and
+// the installers.
+var BootProfiles = []string{"linux", "rescue", "vnc", "windows", "plesk", "cpanel"}
+
+// BootProfile is the boot configuration of a server: the armed profile, if any, and
+// the options it was armed with.
+type BootProfile struct {
+	ActiveProfile   string // linux/rescue/...
+	Architecture    int
+	AuthorizedKeys  []BootKey
+	HostKeys        []BootKey
+	Hostname        string
+	Keyboard        string
+	Language        string
+	OperatingSystem string
+	Password        string
+	ServerNumber    int
+	ServerIPv4      string
+	ServerIPv6      string
+}
+
+// BootKey is an SSH key of a boot profile, an authorized key of the account or a host
+// key of the rescue system.
+type BootKey struct {
+	Fingerprint string
+	Type        string
+	Size        int
+}
+
+// BootProfileOptions are the options a boot profile is armed with.
+type BootProfileOptions struct {
+	// OperatingSystem is the distribution of an installer or the os of the rescue
+	// system.
+	OperatingSystem string
+	// Language of the installation.
+	Language string
+	// Hostname of a plesk or cpanel installation.
+	Hostname string
+	// Architecture is 64 or 32 bit, Robot picks its default when 0. Windows has no
+	// architecture.
+	Architecture int
+	// Keyboard layout of the rescue system, e.g. "us" or "de".
+	Keyboard string
+	// AuthorizedKeys are fingerprints of SSH keys of the account, used by the linux
+	// and rescue profiles.
+	AuthorizedKeys []string
+}
+
+// GetBoot returns the boot configuration of a server.
+func (c *Client) GetBoot(ctx context.Context, serverNumber int) (*BootProfile, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/boot/%d", c.url, serverNumber), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	jsonStr := string(bytes)
+	activeProfile := ""
+
+	for _, profile := range BootProfiles {
+		if gjson.Get(jsonStr, "boot."+profile+".active").Bool() {
+			activeProfile = profile
+		}
+	}
+
+	// without an armed profile the server data is the same in every profile
+	profileJSON := gjson.Get(jsonStr, "boot.rescue").String()
+	if activeProfile != "" {
+		profileJSON = gjson.Get(jsonStr, "boot."+activeProfile).String()
+	}
+
+	bootProfile := parseBootProfile(activeProfile, profileJSON)
+	return &bootProfile, nil
+}
+
+// BootOptions are the values a boot profile can be armed with. Robot only lists them
+// while the profile is not armed, the options of the armed profile are empty.
+type BootOptions struct {
+	Active bool
+	// OperatingSystems are the os of the rescue system or the dist of an installer.
+	OperatingSystems []string
+	Languages        []string
+	Architectures    []int
+	Keyboards        []string
+}
+
+// GetBootOptions returns the options of every boot profile offered for a server, keyed
+// by profile.
+func (c *Client) GetBootOptions(ctx context.Context, serverNumber int) (map[string]BootOptions, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/boot/%d", c.url, serverNumber), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	options := make(map[string]BootOptions)
+	for _, profile := range BootProfiles {
+		profileJSON := gjson.Get(string(bytes), "boot."+profile)
+		if !profileJSON.Exists() || profileJSON.Type == gjson.Null {
+			continue
+		}
+		osField := "dist"
+		if profile == "rescue" {
+			osField = "os"
+		}
+
+		profileOptions := BootOptions{
+			Active:           profileJSON.Get("active").Bool(),
+			OperatingSystems: make([]string, 0),
+			Languages:        make([]string, 0),
+			Architectures:    make([]int, 0),
+			Keyboards:        make([]string, 0),
+		}
+		if !profileOptions.Active {
+			for _, value := range profileJSON.Get(osField).Array() {
+				profileOptions.OperatingSystems = append(profileOptions.OperatingSystems, value.String())
+			}
+			for _, value := range profileJSON.Get("lang").Array() {
+				profileOptions.Languages = append(profileOptions.Languages, value.String())
+			}
+			for _, value := range profileJSON.Get("arch").Array() {
+				profileOptions.Architectures = append(profileOptions.Architectures, int(value.Int()))
+			}
+			for _, value := range profileJSON.Get("keyboard").Array() {
+				profileOptions.Keyboards = append(profileOptions.Keyboards, value.String())
+			}
+		}
+		options[profile] = profileOptions
+	}
+	return options, nil
+}
+
+// SetBootProfile arms a boot profile (one of BootProfiles) for the next boot of a
+// server. Arming the profile which is already active with the same options returns the
+// current configuration, with other options it fails with BOOT_ALREADY_ENABLED.
+func (c *Client) SetBootProfile(ctx context.Context, serverNumber int, profile string, options BootProfileOptions) (*BootProfile, error) {
+	data := url.Values{}
+	switch profile {
+	case "rescue":
+		data.Set("os", options.OperatingSystem)
+	case "windows":
+		// Robot picks the current Windows version without dist
+		if options.OperatingSystem != "" {
+			data.Set("dist", options.OperatingSystem)
+		}
+		data.Set("lang", options.Language)
+	default:
+		data.Set("dist", options.OperatingSystem)
+		data.Set("lang", options.Language)
+	}
+	if profile == "linux" || profile == "rescue" {
+		for _, key := range options.AuthorizedKeys {
+			data.Add("authorized_key", key)
+		}
+	}
+	if profile == "plesk" || profile == "cpanel" {
+		data.Set("hostname", options.Hostname)
+	}
+	if options.Architecture != 0 && profile != "windows" {
+		data.Set("arch", strconv.Itoa(options.Architecture))
+	}
+	if options.Keyboard != "" && profile == "rescue" {
+		data.Set("keyboard", options.Keyboard)
+	}
+
+	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/boot/%d/%s", c.url, serverNumber, profile), data, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		if ErrorCode(err) != "BOOT_ALREADY_ENABLED" {
+			return nil, err
+		}
+		// Robot keeps the options of an armed profile, which only helps if they match
+		bootProfile, getErr := c.GetBoot(ctx, serverNumber)
+		if getErr != nil {
+			return nil, getErr
+		}
+		if bootProfile.ActiveProfile != profile || !bootProfile.matches(options) {
+			return nil, err
+		}
+		return bootProfile, nil
+	}
+
+	// the response only holds the profile which was just activated
+	bootProfile := parseBootProfile(profile, gjson.Get(string(bytes), profile).String())
+	return &bootProfile, nil
+}
+
+// matches reports whether the profile was armed with options. Options Robot picks a
+// default for are only compared when set.
+func (b *BootProfile) matches(options BootProfileOptions) bool {
+	fingerprints := make([]string, 0, len(b.AuthorizedKeys))
+	for _, key := range b.AuthorizedKeys {
+		fingerprints = append(fingerprints, key.Fingerprint)
+	}
+
+	return (options.OperatingSystem == "" || options.OperatingSystem == b.OperatingSystem) &&
+		options.Language == b.Language &&
+		options.Hostname == b.Hostname &&
+		(options.Architecture == 0 || options.Architecture == b.Architecture) &&
+		(options.Keyboard == "" || options.Keyboard == b.Keyboard) &&
+		slices.Equal(slices.Sorted(slices.Values(options.AuthorizedKeys)), slices.Sorted(slices.Values(fingerprints)))
+}
+
+// DeactivateBootProfile disarms a boot profile of a server.
+func (c *Client) DeactivateBootProfile(ctx context.Context, serverNumber int, profile string) error {
+	_, err := c.makeAPICall(ctx, "DELETE", fmt.Sprintf("%s/boot/%d/%s", c.url, serverNumber, profile), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseBootProfile(profile string, profileJSON string) BootProfile {
+	bootProfile := BootProfile{
+		ActiveProfile: profile,
+	}
+
+	switch profile {
+	case "":
+	case "rescue":
+		bootProfile.OperatingSystem = gjson.Get(profileJSON, "os").String()
+		bootProfile.Keyboard = gjson.Get(profileJSON, "keyboard").String()
+	default:
+		bootProfile.Language = gjson.Get(profileJSON, "lang").String()
+		bootProfile.OperatingSystem = gjson.Get(profileJSON, "dist").String()
+		bootProfile.Hostname = gjson.Get(profileJSON, "hostname").String()
+	}
+
+	// lists of the available options while the profile is not armed
+	if arch := gjson.Get(profileJSON, "arch"); arch.Type == gjson.Number {
+		bootProfile.Architecture = int(arch.Int())
+	}
+	bootProfile.AuthorizedKeys = parseBootKeys(gjson.Get(profileJSON, "authorized_key"))
+	bootProfile.HostKeys = parseBootKeys(gjson.Get(profileJSON, "host_key"))
+	bootProfile.Password = gjson.Get(profileJSON, "password").String()
+	bootProfile.ServerNumber = int(gjson.Get(profileJSON, "server_number").Int())
+	bootProfile.ServerIPv4 = gjson.Get(profileJSON, "server_ip").String()
+	bootProfile.ServerIPv6 = gjson.Get(profileJSON, "server_ipv6_net").String()
+
+	return bootProfile
+}
+
+// parseBootKeys parses a list of {"key": {"fingerprint": ..., "type": ..., "size": ...}}.
+func parseBootKeys(keys gjson.Result) []BootKey {
+	result := make([]BootKey, 0)
+	for _, key := range keys.Array() {
+		result = append(result, BootKey{
+			Fingerprint: key.Get("key.fingerprint").String(),
+			Type:        key.Get("key.type").String(),
+			Size:        int(key.Get("key.size").Int()),
+		})
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package robot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+)
+
+func TestSetBootProfileAlreadyEnabled(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	client := testClient(fake)
+	ctx := context.Background()
+
+	if _, err := client.SetBootProfile(ctx, 321, "rescue", BootProfileOptions{OperatingSystem: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// arming it again with the same options returns the armed profile
+	boot, err := client.SetBootProfile(ctx, 321, "rescue", BootProfileOptions{OperatingSystem: "linux"})
+	if err != nil || boot.OperatingSystem != "linux" {
+		t.Errorf("expected the armed profile, got %+v %v", boot, err)
+	}
+
+	// Robot keeps the armed options, which must not pass for the requested ones
+	if _, err := client.SetBootProfile(ctx, 321, "rescue", BootProfileOptions{OperatingSystem: "vkvm"}); ErrorCode(err) != "BOOT_ALREADY_ENABLED" {
+		t.Errorf("expected BOOT_ALREADY_ENABLED for different options, got %v", err)
+	}
+	if boot, err = client.GetBoot(ctx, 321); err != nil || boot.OperatingSystem != "linux" {
+		t.Errorf("expected the fake to keep linux armed, got %+v %v", boot, err)
+	}
+}
@@ -0,0 +1,196 @@
+package robot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/redact"
+)
+
+// DefaultBaseURL is the url of the Robot webservice.
+const DefaultBaseURL = "https://robot-ws.your-server.de"
+
+// DefaultRequestTimeout bounds a single request of clients using the default http.Client.
+const DefaultRequestTimeout = 60 * time.Second
+
+// Client talks to the Robot webservice. It is safe for concurrent use; requests share
+// one rate limiter, so a single Client should be used per Robot account.
+type Client struct {
+	username   string
+	password   string
+	url        string
+	userAgent  string
+	httpClient *http.Client
+	retry      retryConfig
+	limiter    *requestScheduler
+
+	maskedKeys map[string]bool
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL points the client at another webservice url, e.g. a local stand-in.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.url = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient replaces the http.Client used to send requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithRetry configures how often rate limited, failed or unreachable requests are
+// retried and the bounds of the exponential backoff between two attempts.
+func WithRetry(maxRetries int, waitMin time.Duration, waitMax time.Duration) Option {
+	return func(c *Client) {
+		c.retry = retryConfig{
+			maxRetries: maxRetries,
+			waitMin:    waitMin,
+			waitMax:    waitMax,
+		}
+	}
+}
+
+// WithRateLimits overrides the hourly request budget per endpoint family (e.g.
+// "firewall"). A limit of 0 disables throttling for the family.
+func WithRateLimits(limits map[string]int) Option {
+	return func(c *Client) {
+		c.limiter = newRequestScheduler(limits)
+	}
+}
+
+// WithMaskedKeys masks the values of additional form and JSON keys in debug logs.
+func WithMaskedKeys(keys ...string) Option {
+	return func(c *Client) {
+		c.maskedKeys = redact.KeySet(redact.LogKeys, keys...)
+	}
+}
+
+// NewClient returns a client authenticating with the given webservice credentials.
+func NewClient(username string, password string, options ...Option) *Client {
+	c := &Client{
+		username:   username,
+		password:   password,
+		url:        DefaultBaseURL,
+		httpClient: &http.Client{Timeout: DefaultRequestTimeout},
+		retry:      defaultRetryConfig(),
+		limiter:    newRequestScheduler(nil),
+
+		maskedKeys: redact.KeySet(redact.LogKeys),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+func codeIsInExpected(statusCode int, expectedStatusCodes []int) bool {
+	for _, expectedStatusCode := range expectedStatusCodes {
+		if statusCode == expectedStatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) makeAPICall(ctx context.Context, method string, uri string, data url.Values, expectedStatusCodes []int) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		responseBytes, err := c.doAPICall(ctx, method, uri, data, expectedStatusCodes)
+		if err == nil || attempt >= c.retry.maxRetries || !isRetryable(ctx, method, err) {
+			return responseBytes, err
+		}
+
+		wait := c.retry.backoff(attempt, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return nil, err
+		}
+
+		tflog.Warn(c.logContext(ctx), "retrying Hetzner webservice request", map[string]interface{}{
+			"uri":     uri,
+			"method":  method,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+			"error":   err.Error(),
+		})
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) doAPICall(ctx context.Context, method string, uri string, data url.Values, expectedStatusCodes []int) ([]byte, error) {
+	if err := c.limiter.wait(ctx, endpointFamily(c.url, uri)); err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, uri, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	if data != nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	if c.userAgent != "" {
+		request.Header.Set("User-Agent", c.userAgent)
+	}
+
+	request.SetBasicAuth(c.username, c.password)
+
+	logCtx := c.logContext(ctx)
+	tflog.Debug(logCtx, "requesting Hetzner webservice", map[string]interface{}{
+		"uri":     uri,
+		"method":  method,
+		"headers": redactHeaders(c.maskedKeys, request.Header),
+		"data":    redactValues(c.maskedKeys, data),
+	})
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+
+	defer response.Body.Close()
+
+	responseBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	tflog.Debug(logCtx, "got hetzner webservice response", map[string]interface{}{
+		"status": response.StatusCode,
+		"body":   redactJSON(c.maskedKeys, responseBytes),
+	})
+
+	if !codeIsInExpected(response.StatusCode, expectedStatusCodes) {
+		apiError := newAPIError(response.StatusCode, responseBytes)
+		apiError.retryAfter = parseRetryAfter(response.Header.Get("Retry-After"))
+		return nil, apiError
+	}
+
+	return responseBytes, nil
+}
@@ -0,0 +1,287 @@
+package robot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/redact"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/robotfake"
+)
+
+// testClient returns a client talking to fake, with retries fast enough for tests.
+func testClient(fake *robotfake.Server) *Client {
+	return NewClient(robotfake.Username, robotfake.Password,
+		WithBaseURL(fake.URL),
+		WithRetry(3, time.Millisecond, 10*time.Millisecond),
+	)
+}
+
+func TestNewAPIError(t *testing.T) {
+	apiError := newAPIError(http.StatusBadRequest, []byte(`{"error":{"status":400,"code":"INVALID_INPUT","message":"invalid input","missing":null,"invalid":["rules[input][0][src_ip]"]}}`))
+
+	if !errors.Is(apiError, ErrInvalidInput) {
+		t.Error("expected ErrInvalidInput")
+	}
+	if errors.Is(apiError, ErrNotFound) {
+		t.Error("did not expect ErrNotFound")
+	}
+	if len(apiError.Invalid) != 1 || apiError.Invalid[0] != "rules[input][0][src_ip]" {
+		t.Errorf("unexpected invalid fields: %v", apiError.Invalid)
+	}
+
+	for code, sentinel := range map[string]error{
+		"SERVER_NOT_FOUND":     ErrNotFound,
+		"FIREWALL_IN_PROCESS":  ErrConflict,
+		"BOOT_ALREADY_ENABLED": ErrConflict,
+		"RATE_LIMIT_EXCEEDED":  ErrRateLimitExceeded,
+		"UNAUTHORIZED":         ErrUnauthorized,
+		"INTERNAL_ERROR":       ErrServerError,
+	} {
+		apiError := &APIError{StatusCode: http.StatusTeapot, Code: code}
+		if !errors.Is(apiError, sentinel) {
+			t.Errorf("expected %s to match %v", code, sentinel)
+		}
+	}
+
+	raw := newAPIError(http.StatusBadGateway, []byte("<html>bad gateway</html>"))
+	if !errors.Is(raw, ErrServerError) || !strings.Contains(raw.Error(), "bad gateway") {
+		t.Errorf("unexpected error for a non JSON body: %v", raw)
+	}
+}
+
+func TestMakeAPICallRetries(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(1, "192.0.2.1", "one")
+	client := testClient(fake)
+	ctx := context.Background()
+
+	fake.FailNext(http.MethodGet, "/server/1", http.StatusServiceUnavailable, "INTERNAL_ERROR")
+	if _, err := client.GetServer(ctx, 1); err != nil {
+		t.Fatalf("expected the GET to be retried: %v", err)
+	}
+
+	// a POST which may have been processed is not replayed
+	fake.FailNext(http.MethodPost, "/vswitch", http.StatusInternalServerError, "INTERNAL_ERROR")
+	if _, err := client.CreateVSwitch(ctx, "test", 4000); !errors.Is(err, ErrServerError) {
+		t.Fatalf("expected the POST to fail, got %v", err)
+	}
+
+	// while a rate limited one is
+	fake.FailNext(http.MethodPost, "/vswitch", http.StatusForbidden, "RATE_LIMIT_EXCEEDED")
+	if _, err := client.CreateVSwitch(ctx, "test", 4000); err != nil {
+		t.Fatalf("expected the rate limited POST to be retried: %v", err)
+	}
+
+	fake.SetRateLimit("server", 1)
+	if _, err := client.GetServer(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetServer(ctx, 1); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Fatalf("expected the retries to run out, got %v", err)
+	}
+	if requests := fake.Requests("server"); requests != 1+1+client.retry.maxRetries {
+		t.Errorf("expected %d requests, got %d", 2+client.retry.maxRetries, requests)
+	}
+}
+
+func TestMakeAPICallStopsAtDeadline(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(1, "192.0.2.1", "one")
+	client := testClient(fake)
+	client.retry = retryConfig{maxRetries: 10, waitMin: time.Hour, waitMax: time.Hour}
+
+	fake.FailNext(http.MethodGet, "/server/1", http.StatusInternalServerError, "INTERNAL_ERROR")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.GetServer(ctx, 1); !errors.Is(err, ErrServerError) {
+		t.Fatalf("expected the original error, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("expected to give up instead of waiting past the deadline")
+	}
+}
+
+func TestRequestSchedulerThrottles(t *testing.T) {
+	scheduler := newRequestScheduler(map[string]int{"firewall": 2, "boot": 0})
+	budget := scheduler.budget("firewall")
+	now := time.Now()
+	budget.last = now
+
+	if wait := budget.reserve(now); wait != 0 {
+		t.Errorf("expected the first request to pass, waited %s", wait)
+	}
+	if wait := budget.reserve(now); wait != 0 {
+		t.Errorf("expected the second request to pass, waited %s", wait)
+	}
+	if wait := budget.reserve(now); wait != 30*time.Minute {
+		t.Errorf("expected the third request to wait for half an hour, waited %s", wait)
+	}
+
+	if scheduler.budget("boot") != nil {
+		t.Error("expected a limit of 0 to disable throttling")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := scheduler.wait(ctx, "firewall"); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected waiting to respect the context, got %v", err)
+	}
+
+	if family := endpointFamily("https://robot-ws.your-server.de", "https://robot-ws.your-server.de/firewall/1"); family != "firewall" {
+		t.Errorf("unexpected endpoint family %q", family)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	maskedKeys := redact.KeySet(redact.LogKeys, "Name")
+
+	values := redactValues(maskedKeys, url.Values{
+		"authorized_key":          {"aa:bb", "cc:dd"},
+		"os":                      {"linux"},
+		"rules[input][0][name]":   {"ssh"},
+		"rules[input][0][action]": {"accept"},
+	})
+	if values.Get("authorized_key") != redactedValue || values.Get("os") != "linux" {
+		t.Errorf("unexpected form values: %v", values)
+	}
+	if values.Get("rules[input][0][name]") != redactedValue || values.Get("rules[input][0][action]") != "accept" {
+		t.Errorf("unexpected rule values: %v", values)
+	}
+
+	body := redactJSON(maskedKeys, []byte(`{"rescue":{"server_ip":"192.0.2.1","password":"hunter2","authorized_key":[{"key":{"fingerprint":"aa"}}]}}`))
+	if strings.Contains(body, "hunter2") || strings.Contains(body, "fingerprint") || !strings.Contains(body, "192.0.2.1") {
+		t.Errorf("unexpected body: %s", body)
+	}
+	if body := redactJSON(maskedKeys, []byte(`{"key":{"type":"ED25519","data":"ssh-ed25519 AAAA"}}`)); strings.Contains(body, "ssh-ed25519 AAAA") || !strings.Contains(body, `"type":"ED25519"`) {
+		t.Errorf("expected the key material to be masked: %s", body)
+	}
+
+	headers := redactHeaders(maskedKeys, http.Header{"Authorization": {"Basic cm9ib3Q6c2VjcmV0"}, "Content-Type": {"application/json"}})
+	if headers["Authorization"] != redactedValue || headers["Content-Type"] != "application/json" {
+		t.Errorf("unexpected headers: %v", headers)
+	}
+}
+
+func TestRequestLogging(t *testing.T) {
+	fake := robotfake.New(t)
+	fake.AddServer(321, "192.0.2.10", "boot")
+	client := testClient(fake)
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	if _, err := client.SetBootProfile(ctx, 321, "rescue", BootProfileOptions{OperatingSystem: "linux", AuthorizedKeys: []string{"aa:bb"}}); err == nil {
+		t.Fatal("expected the unknown key to be rejected")
+	}
+	if _, err := client.SetBootProfile(ctx, 321, "rescue", BootProfileOptions{OperatingSystem: "linux"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(output.String(), robotfake.Password) || strings.Contains(output.String(), "pw321") {
+		t.Errorf("the account or rescue password was logged: %s", output.String())
+	}
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests, responses []map[string]interface{}
+	for _, entry := range entries {
+		switch entry["@message"] {
+		case "requesting Hetzner webservice":
+			requests = append(requests, entry)
+		case "got hetzner webservice response":
+			responses = append(responses, entry)
+		}
+	}
+	if len(requests) != 2 || len(responses) != 2 {
+		t.Fatalf("expected two requests and responses, got %v", entries)
+	}
+
+	form, ok := requests[0]["data"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected the request form to be logged, got %v", requests[0]["data"])
+	}
+	if fmt.Sprint(form["os"]) != "[linux]" || fmt.Sprint(form["authorized_key"]) != "["+redactedValue+"]" {
+		t.Errorf("unexpected request form %v", form)
+	}
+	if body := fmt.Sprint(responses[1]["body"]); !strings.Contains(body, `"os":"linux"`) || !strings.Contains(body, `"password":"`+redactedValue+`"`) {
+		t.Errorf("unexpected response body %s", body)
+	}
+}
+
+func TestListHelpers(t *testing.T) {
+	fake := robotfake.New(t)
+	client := testClient(fake)
+	ctx := context.Background()
+
+	// Robot answers lists of an empty account with a not found error
+	if servers, err := client.ListServers(ctx); err != nil || len(servers) != 0 {
+		t.Fatalf("expected no servers, got %v %v", servers, err)
+	}
+	if keys, err := client.ListSSHKeys(ctx); err != nil || len(keys) != 0 {
+		t.Fatalf("expected no keys, got %v %v", keys, err)
+	}
+	if templates, err := client.ListFirewallTemplates(ctx); err != nil || len(templates) != 0 {
+		t.Fatalf("expected no firewall templates, got %v %v", templates, err)
+	}
+
+	fake.AddServer(1, "192.0.2.1", "one")
+	fake.AddServer(2, "192.0.2.2", "two")
+
+	servers, err := client.ListServers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) != 2 || servers[0].ServerName != "one" || servers[1].ServerNumber != 2 {
+		t.Errorf("unexpected servers %+v", servers)
+	}
+
+	server, err := client.GetServerByIP(ctx, "192.0.2.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.ServerNumber != 2 {
+		t.Errorf("expected server 2, got %d", server.ServerNumber)
+	}
+	if _, err := client.GetServerByIP(ctx, "192.0.2.3"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := client.CreateVSwitch(ctx, "private", 4010); err != nil {
+		t.Fatal(err)
+	}
+	vSwitches, err := client.ListVSwitches(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vSwitches) != 1 || vSwitches[0].Vlan != 4010 {
+		t.Errorf("unexpected vSwitches %+v", vSwitches)
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	client := NewClient("user", "password",
+		WithBaseURL("http://localhost:8080/"),
+		WithUserAgent("robot-test"),
+		WithRateLimits(map[string]int{"firewall": 10}),
+	)
+
+	if client.url != "http://localhost:8080" || client.userAgent != "robot-test" {
+		t.Errorf("unexpected client %+v", client)
+	}
+	if client.limiter.limits["firewall"] != 10 || client.limiter.limits["boot"] != defaultRateLimits["boot"] {
+		t.Errorf("unexpected rate limits: %v", client.limiter.limits)
+	}
+	if client.retry != defaultRetryConfig() {
+		t.Errorf("expected the default retries, got %+v", client.retry)
+	}
+}
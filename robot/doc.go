@@ -0,0 +1,19 @@
+// Package robot is a client for the Hetzner Robot webservice
+// (https://robot.your-server.de/doc/webservice/en.html), covering the servers, boot
+// configurations, firewalls, SSH keys and vSwitches of an account.
+//
+// Requests are throttled per endpoint family to stay within the documented Robot
+// limits, and rate limited, failed or unreachable requests are retried with an
+// exponential backoff; non-idempotent POST requests are only retried when Robot
+// guarantees they were rejected. Errors returned by the webservice are *APIError
+// values, which can be matched with errors.Is against ErrNotFound, ErrConflict and
+// the other sentinel errors:
+//
+//	client := robot.NewClient(username, password)
+//	server, err := client.GetServer(ctx, 321)
+//	if errors.Is(err, robot.ErrNotFound) {
+//		// cancelled or never ordered
+//	}
+//
+// Robot does not paginate; the List methods return every object of the account.
+package robot
@@ -0,0 +1,102 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors matched by APIError through errors.Is.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrRateLimitExceeded = errors.New("rate limit exceeded")
+	ErrConflict          = errors.New("conflict")
+	ErrInvalidInput      = errors.New("invalid input")
+	ErrServerError       = errors.New("server error")
+)
+
+type errorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// APIError is the decoded form of the error envelope returned by the Robot webservice.
+type APIError struct {
+	StatusCode int      `json:"status"`
+	Code       string   `json:"code"`
+	Message    string   `json:"message"`
+	Missing    []string `json:"missing"`
+	Invalid    []string `json:"invalid"`
+
+	retryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("hetzner webservice response status %d", e.StatusCode)
+	if e.Code != "" {
+		msg = fmt.Sprintf("%s %s", msg, e.Code)
+	}
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	if len(e.Missing) > 0 {
+		msg = fmt.Sprintf("%s (missing: %s)", msg, strings.Join(e.Missing, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		msg = fmt.Sprintf("%s (invalid: %s)", msg, strings.Join(e.Invalid, ", "))
+	}
+	return msg
+}
+
+// Is allows errors.Is(err, ErrNotFound) and friends to match on the Robot error code
+// with a fallback on the HTTP status when the code is unknown.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Code == "NOT_FOUND" || strings.HasSuffix(e.Code, "_NOT_FOUND") || e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.Code == "UNAUTHORIZED" || e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimitExceeded:
+		return e.Code == "RATE_LIMIT_EXCEEDED"
+	case ErrConflict:
+		return e.Code == "CONFLICT" || strings.HasSuffix(e.Code, "_IN_PROCESS") || strings.HasSuffix(e.Code, "_ALREADY_ENABLED") || e.StatusCode == http.StatusConflict
+	case ErrInvalidInput:
+		return e.Code == "INVALID_INPUT" || len(e.Missing) > 0 || len(e.Invalid) > 0
+	case ErrServerError:
+		return e.Code == "INTERNAL_ERROR" || e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// newAPIError decodes a Robot error envelope. Responses which do not carry one
+// (e.g. a proxy error page) still produce a APIError holding the raw body.
+func newAPIError(statusCode int, body []byte) *APIError {
+	response := errorResponse{}
+	if err := json.Unmarshal(body, &response); err != nil || response.Error.Code == "" {
+		return &APIError{
+			StatusCode: statusCode,
+			Message:    strings.TrimSpace(string(body)),
+		}
+	}
+
+	apiError := response.Error
+	if apiError.StatusCode == 0 {
+		apiError.StatusCode = statusCode
+	}
+	return &apiError
+}
+
+// ErrorCode returns the Robot error code (e.g. "BOOT_ALREADY_ENABLED") of err, or an
+// empty string when err does not originate from the webservice.
+func ErrorCode(err error) string {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.Code
+	}
+	return ""
+}
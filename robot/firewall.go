@@ -0,0 +1,110 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#firewall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type firewallResponse struct {
+	Firewall Firewall `json:"firewall"`
+}
+
+// Firewall is the firewall configuration of a server.
+type Firewall struct {
+	IP                       string        `json:"server_ip"`
+	ServerNumber             int           `json:"server_number"`
+	WhitelistHetznerServices bool          `json:"whitelist_hos"`
+	FilterIPv6               bool          `json:"filter_ipv6"`
+	Status                   string        `json:"status"`
+	Port                     string        `json:"port"`
+	Rules                    FirewallRules `json:"rules"`
+}
+
+// FirewallRules are the rules of a firewall, per chain.
+type FirewallRules struct {
+	Input  []FirewallRule `json:"input"`
+	Output []FirewallRule `json:"output"`
+}
+
+// FirewallRule is a single firewall rule. An empty IPVersion matches IPv4 and IPv6.
+type FirewallRule struct {
+	IPVersion string `json:"ip_version"`
+	Name      string `json:"name"`
+	DstIP     string `json:"dst_ip"`
+	DstPort   string `json:"dst_port"`
+	SrcIP     string `json:"src_ip"`
+	SrcPort   string `json:"src_port"`
+	Protocol  string `json:"protocol"`
+	TCPFlags  string `json:"tcp_flags"`
+	Action    string `json:"action"`
+}
+
+// GetFirewall returns the firewall of a server, identified by its number or main IP.
+func (c *Client) GetFirewall(ctx context.Context, server string) (*Firewall, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/firewall/%s", c.url, server), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	firewall := firewallResponse{}
+	if err = json.Unmarshal(bytes, &firewall); err != nil {
+		return nil, err
+	}
+	return &firewall.Firewall, nil
+}
+
+// SetFirewall replaces the firewall configuration of the server firewall.IP.
+func (c *Client) SetFirewall(ctx context.Context, firewall Firewall) error {
+	data := url.Values{}
+
+	data.Set("whitelist_hos", strconv.FormatBool(firewall.WhitelistHetznerServices))
+	data.Set("filter_ipv6", strconv.FormatBool(firewall.FilterIPv6))
+	data.Set("status", firewall.Status)
+
+	setFirewallRules(data, "input", firewall.Rules.Input)
+	setFirewallRules(data, "output", firewall.Rules.Output)
+
+	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/%s", c.url, firewall.IP), data, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// setFirewallRules adds rules as rules[<chain>][N][field] form values.
+func setFirewallRules(data url.Values, chain string, rules []FirewallRule) {
+	for idx, rule := range rules {
+		fields := [][2]string{
+			{"ip_version", rule.IPVersion},
+			{"name", rule.Name},
+			{"dst_ip", rule.DstIP},
+			{"dst_port", rule.DstPort},
+			{"src_ip", rule.SrcIP},
+			{"src_port", rule.SrcPort},
+			{"protocol", rule.Protocol},
+			{"tcp_flags", rule.TCPFlags},
+		}
+		for _, field := range fields {
+			if field[1] != "" {
+				data.Set(fmt.Sprintf("rules[%s][%d][%s]", chain, idx, field[0]), field[1])
+			}
+		}
+		data.Set(fmt.Sprintf("rules[%s][%d][%s]", chain, idx, "action"), rule.Action)
+	}
+}
+
+// DeleteFirewall clears all rules of the firewall of a server.
+func (c *Client) DeleteFirewall(ctx context.Context, server string) error {
+	_, err := c.makeAPICall(ctx, "DELETE", fmt.Sprintf("%s/firewall/%s", c.url, server), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#firewall
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type firewallTemplateResponse struct {
+	Template FirewallTemplate `json:"firewall_template"`
+}
+
+// FirewallTemplate is a reusable firewall configuration which can be applied to
+// servers. Robot copies it on application, later changes do not affect servers.
+type FirewallTemplate struct {
+	ID                       int           `json:"id"`
+	Name                     string        `json:"name"`
+	WhitelistHetznerServices bool          `json:"whitelist_hos"`
+	FilterIPv6               bool          `json:"filter_ipv6"`
+	IsDefault                bool          `json:"is_default"`
+	Rules                    FirewallRules `json:"rules"`
+}
+
+// GetFirewallTemplate returns the template with the given id, including its rules.
+func (c *Client) GetFirewallTemplate(ctx context.Context, id int) (*FirewallTemplate, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/firewall/template/%d", c.url, id), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	response := firewallTemplateResponse{}
+	if err = json.Unmarshal(bytes, &response); err != nil {
+		return nil, err
+	}
+	return &response.Template, nil
+}
+
+// ListFirewallTemplates returns every template of the account. Robot leaves out the
+// rules, use GetFirewallTemplate for those.
+func (c *Client) ListFirewallTemplates(ctx context.Context) ([]FirewallTemplate, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/firewall/template", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if ErrorCode(err) == "NOT_FOUND" {
+		return []FirewallTemplate{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []firewallTemplateResponse
+	if err = json.Unmarshal(bytes, &responses); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal firewall templates: %w", err)
+	}
+
+	templates := make([]FirewallTemplate, len(responses))
+	for i, response := range responses {
+		templates[i] = response.Template
+	}
+	return templates, nil
+}
+
+// CreateFirewallTemplate creates a template, template.ID is ignored.
+func (c *Client) CreateFirewallTemplate(ctx context.Context, template FirewallTemplate) (*FirewallTemplate, error) {
+	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/template", c.url), firewallTemplateData(template), []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	response := firewallTemplateResponse{}
+	if err = json.Unmarshal(bytes, &response); err != nil {
+		return nil, err
+	}
+	return &response.Template, nil
+}
+
+// UpdateFirewallTemplate replaces the template template.ID.
+func (c *Client) UpdateFirewallTemplate(ctx context.Context, template FirewallTemplate) (*FirewallTemplate, error) {
+	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/template/%d", c.url, template.ID), firewallTemplateData(template), []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	response := firewallTemplateResponse{}
+	if err = json.Unmarshal(bytes, &response); err != nil {
+		return nil, err
+	}
+	return &response.Template, nil
+}
+
+// DeleteFirewallTemplate deletes a template. Servers it was applied to keep their rules.
+func (c *Client) DeleteFirewallTemplate(ctx context.Context, id int) error {
+	_, err := c.makeAPICall(ctx, "DELETE", fmt.Sprintf("%s/firewall/template/%d", c.url, id), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ApplyFirewallTemplate replaces the firewall configuration of a server by a template
+// and sets its status ("active" / "disabled").
+func (c *Client) ApplyFirewallTemplate(ctx context.Context, server string, templateID int, status string) error {
+	data := url.Values{}
+	data.Set("template_id", strconv.Itoa(templateID))
+	data.Set("status", status)
+
+	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/firewall/%s", c.url, server), data, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func firewallTemplateData(template FirewallTemplate) url.Values {
+	data := url.Values{}
+	data.Set("name", template.Name)
+	data.Set("whitelist_hos", strconv.FormatBool(template.WhitelistHetznerServices))
+	data.Set("filter_ipv6", strconv.FormatBool(template.FilterIPv6))
+	data.Set("is_default", strconv.FormatBool(template.IsDefault))
+
+	setFirewallRules(data, "input", template.Rules.Input)
+	setFirewallRules(data, "output", template.Rules.Output)
+	return data
+}
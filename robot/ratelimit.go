@@ -0,0 +1,148 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#request-limits
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Hourly request limits documented by Robot, per endpoint family.
+var defaultRateLimits = map[string]int{
+	"boot":     500,
+	"firewall": 500,
+	"key":      500,
+	"reset":    50,
+	"server":   200,
+	"vswitch":  500,
+}
+
+// requestScheduler hands out requests from a token bucket per endpoint family. It is
+// shared by every resource of a configured provider, so requests block instead of
+// running into RATE_LIMIT_EXCEEDED.
+type requestScheduler struct {
+	mu      sync.Mutex
+	limits  map[string]int
+	budgets map[string]*requestBudget
+}
+
+type requestBudget struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+// newRequestScheduler returns a scheduler using the default limits, overridden by
+// limits. A limit of 0 disables throttling for the family.
+func newRequestScheduler(limits map[string]int) *requestScheduler {
+	merged := make(map[string]int, len(defaultRateLimits)+len(limits))
+	for family, limit := range defaultRateLimits {
+		merged[family] = limit
+	}
+	for family, limit := range limits {
+		merged[family] = limit
+	}
+
+	return &requestScheduler{
+		limits:  merged,
+		budgets: make(map[string]*requestBudget),
+	}
+}
+
+func (s *requestScheduler) budget(family string) *requestBudget {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if budget, ok := s.budgets[family]; ok {
+		return budget
+	}
+
+	limit := s.limits[family]
+	if limit <= 0 {
+		return nil
+	}
+
+	budget := &requestBudget{
+		capacity: float64(limit),
+		tokens:   float64(limit),
+		rate:     float64(limit) / time.Hour.Seconds(),
+		last:     time.Now(),
+	}
+	s.budgets[family] = budget
+	return budget
+}
+
+// wait blocks until a request of the given endpoint family may be sent or ctx is done.
+func (s *requestScheduler) wait(ctx context.Context, family string) error {
+	if s == nil {
+		return nil
+	}
+
+	budget := s.budget(family)
+	if budget == nil {
+		return nil
+	}
+
+	delay := budget.reserve(time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	tflog.Info(ctx, "throttling Hetzner webservice request", map[string]interface{}{
+		"endpoint": family,
+		"limit":    s.limits[family],
+		"wait":     delay.Round(time.Millisecond).String(),
+	})
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		budget.cancel()
+		return fmt.Errorf("waiting for %s request budget: %w", family, ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait until it is
+// covered. Tokens may go negative, which queues concurrent callers behind each other.
+func (b *requestBudget) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *requestBudget) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.capacity, b.tokens+1)
+}
+
+// endpointFamily returns the first path segment of uri below the webservice base url,
+// e.g. "firewall" for https://robot-ws.your-server.de/firewall/123.
+func endpointFamily(baseURL string, uri string) string {
+	path := strings.TrimPrefix(strings.TrimPrefix(uri, baseURL), "/")
+	if idx := strings.IndexAny(path, "/?"); idx >= 0 {
+		path = path[:idx]
+	}
+	return path
+}
@@ -0,0 +1,109 @@
+package robot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/hetznerrobot/recorder"
+)
+
+const (
+	testSshKeyData        = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHS5pQGk7lk5rizDVjDMgOah7psFyfJ0+Gpb+Nk7BBxe test"
+	testSshKeyFingerprint = "59:af:34:3f:44:73:09:20:9d:ca:13:e9:7e:52:66:f0"
+)
+
+// Tests in this file replay cassettes recorded with HETZNERROBOT_RECORD=1, see
+// testRecordedClient.
+
+func TestRecordedSshKeyLifecycle(t *testing.T) {
+	client := testRecordedClient(t, "ssh_key_lifecycle")
+	ctx := context.Background()
+
+	key, err := client.CreateSSHKey(ctx, "recorded", testSshKeyData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Fingerprint != testSshKeyFingerprint || key.Type != "ED25519" || key.Data != testSshKeyData {
+		t.Errorf("unexpected key %+v", key)
+	}
+
+	key, err = client.UpdateSSHKey(ctx, key.Fingerprint, "recorded-renamed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Name != "recorded-renamed" {
+		t.Errorf("expected key to be renamed, got %q", key.Name)
+	}
+
+	if err := client.DeleteSSHKey(ctx, key.Fingerprint); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetSSHKey(ctx, key.Fingerprint); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected deleted key to be gone, got %v", err)
+	}
+}
+
+func TestRecordedServers(t *testing.T) {
+	client := testRecordedClient(t, "servers")
+	ctx := context.Background()
+
+	servers, err := client.ListServers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(servers) == 0 {
+		t.Fatal("expected at least one server")
+	}
+
+	server, err := client.GetServer(ctx, servers[0].ServerNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.ServerIP != servers[0].ServerIP {
+		t.Errorf("expected server ip %s, got %s", servers[0].ServerIP, server.ServerIP)
+	}
+}
+
+// testRecordedClient returns a client replaying testdata/<name>.json. With
+// HETZNERROBOT_RECORD=1 it talks to the webservice configured through the
+// HETZNERROBOT_* environment variables instead and (re)records the cassette.
+func testRecordedClient(t *testing.T, name string) *Client {
+	t.Helper()
+
+	cassette := filepath.Join("testdata", name+".json")
+	mode := recorder.ModeFromEnv()
+
+	url := DefaultBaseURL
+	if mode == recorder.ModeRecord {
+		if os.Getenv("HETZNERROBOT_USERNAME") == "" || os.Getenv("HETZNERROBOT_PASSWORD") == "" {
+			t.Fatal("HETZNERROBOT_USERNAME and HETZNERROBOT_PASSWORD must be set to record")
+		}
+		if env := os.Getenv("HETZNERROBOT_URL"); env != "" {
+			url = env
+		}
+	}
+
+	rec, err := recorder.New(cassette, mode, nil, recorder.Options{
+		AnonymizeIPs: os.Getenv(recorder.EnvRecordAnonymize) == "1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode == recorder.ModeReplay {
+		t.Cleanup(func() {
+			if unused := rec.Unused(); len(unused) > 0 && !t.Failed() {
+				t.Errorf("%d interactions of %s were not replayed, first: %s %s", len(unused), cassette, unused[0].Method, unused[0].URI)
+			}
+		})
+	}
+
+	return NewClient(os.Getenv("HETZNERROBOT_USERNAME"), os.Getenv("HETZNERROBOT_PASSWORD"),
+		WithBaseURL(url),
+		WithHTTPClient(&http.Client{Transport: rec}),
+		WithRetry(0, 0, 0),
+	)
+}
@@ -0,0 +1,88 @@
+package robot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/strng-solutions/terraform-provider-hetzner-robot/internal/redact"
+)
+
+const redactedValue = redact.Value
+
+// logContext masks the account password anywhere in log messages, as a second line of
+// defence next to redactValues/redactJSON. The masked keys are form and JSON keys, not
+// log field keys: masking log fields by them would hide whole payloads, e.g. the
+// "data" field holding the request form.
+func (c *Client) logContext(ctx context.Context) context.Context {
+	if c.password != "" {
+		ctx = tflog.MaskAllFieldValuesStrings(ctx, c.password)
+	}
+	return ctx
+}
+
+func redactValues(maskedKeys map[string]bool, data url.Values) url.Values {
+	if data == nil {
+		return nil
+	}
+	redacted := make(url.Values, len(data))
+	for key, values := range data {
+		if !redact.Masked(maskedKeys, key) {
+			redacted[key] = values
+			continue
+		}
+		masked := make([]string, len(values))
+		for i := range values {
+			masked[i] = redactedValue
+		}
+		redacted[key] = masked
+	}
+	return redacted
+}
+
+func redactHeaders(maskedKeys map[string]bool, header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for key := range header {
+		if redact.Masked(maskedKeys, key) {
+			redacted[key] = redactedValue
+			continue
+		}
+		redacted[key] = header.Get(key)
+	}
+	return redacted
+}
+
+// redactJSON masks the values of masked keys anywhere in a JSON document. Bodies which
+// are not JSON are returned unchanged.
+func redactJSON(maskedKeys map[string]bool, body []byte) string {
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(redactJSONValue(maskedKeys, document))
+	if err != nil {
+		return string(body)
+	}
+	return string(redacted)
+}
+
+func redactJSONValue(maskedKeys map[string]bool, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if redact.Masked(maskedKeys, key) && child != nil {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactJSONValue(maskedKeys, child)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactJSONValue(maskedKeys, child)
+		}
+	}
+	return value
+}
@@ -0,0 +1,23 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#reset
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ResetTypes are the reset types accepted by ResetServer: a hardware reset, a
+// software reset via CTRL+ALT+DEL and a power cycle.
+var ResetTypes = []string{"hw", "sw", "power"}
+
+// ResetServer resets a server, which boots an armed boot profile.
+func (c *Client) ResetServer(ctx context.Context, serverNumber int, resetType string) error {
+	data := url.Values{}
+	data.Set("type", resetType)
+
+	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/reset/%d", c.url, serverNumber), data, []int{http.StatusOK, http.StatusAccepted})
+	return err
+}
@@ -0,0 +1,103 @@
+package robot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Retry defaults of a Client, see WithRetry.
+const (
+	DefaultMaxRetries   = 5
+	DefaultRetryWaitMin = 1 * time.Second
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// Error codes for which Robot guarantees the request was rejected before doing anything,
+// so even non-idempotent POST requests can be replayed safely.
+var safePostRetryCodes = map[string]bool{
+	"RATE_LIMIT_EXCEEDED": true,
+}
+
+type retryConfig struct {
+	maxRetries int
+	waitMin    time.Duration
+	waitMax    time.Duration
+}
+
+func defaultRetryConfig() retryConfig {
+	return retryConfig{
+		maxRetries: DefaultMaxRetries,
+		waitMin:    DefaultRetryWaitMin,
+		waitMax:    DefaultRetryWaitMax,
+	}
+}
+
+// isRetryable reports whether a failed request may be sent again.
+func isRetryable(ctx context.Context, method string, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		if safePostRetryCodes[apiError.Code] {
+			return true
+		}
+		if apiError.StatusCode < http.StatusInternalServerError {
+			return false
+		}
+		return method != http.MethodPost
+	}
+
+	// A failed dial means the request never left this host.
+	var opError *net.OpError
+	if errors.As(err, &opError) && opError.Op == "dial" {
+		return true
+	}
+
+	var netError net.Error
+	if errors.As(err, &netError) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return method != http.MethodPost
+	}
+
+	return false
+}
+
+// backoff returns the wait before the given (zero based) retry attempt: an exponential
+// backoff between waitMin and waitMax with full jitter, unless Robot asked for a
+// specific delay through Retry-After.
+func (r retryConfig) backoff(attempt int, err error) time.Duration {
+	var apiError *APIError
+	if errors.As(err, &apiError) && apiError.retryAfter > 0 {
+		return apiError.retryAfter
+	}
+
+	wait := float64(r.waitMin) * math.Pow(2, float64(attempt))
+	if wait > float64(r.waitMax) || math.IsInf(wait, 1) {
+		wait = float64(r.waitMax)
+	}
+	if wait <= float64(r.waitMin) {
+		return r.waitMin
+	}
+	return r.waitMin + time.Duration(rand.Int63n(int64(wait)-int64(r.waitMin)))
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type serverResponse struct {
+	Server Server `json:"server"`
+}
+
+type ServerSubnet struct {
+	IP   string `json:"ip"`
+	Mask string `json:"mask"`
+}
+
+// Server is a dedicated server of the account.
+type Server struct {
+	ServerIP         string         `json:"server_ip"`
+	ServerIPv6       string         `json:"server_ipv6_net"`
+	ServerNumber     int            `json:"server_number"`
+	ServerName       string         `json:"server_name"`
+	Product          string         `json:"product"`
+	DataCenter       string         `json:"dc"`
+	Traffic          string         `json:"traffic"`
+	Status           string         `json:"status"`
+	Cancelled        bool           `json:"cancelled"`
+	PaidUntil        string         `json:"paid_until"`
+	IPs              []string       `json:"ip"`
+	Subnets          []ServerSubnet `json:"subnet"`
+	LinkedStoragebox int            `json:"linked_storagebox"`
+
+	Reset   bool `json:"reset"`
+	Rescue  bool `json:"rescue"`
+	VNC     bool `json:"vnc"`
+	Windows bool `json:"windows"`
+	Plesk   bool `json:"plesk"`
+	CPanel  bool `json:"cpanel"`
+	Wol     bool `json:"wol"`
+	HotSwap bool `json:"hot_swap"`
+}
+
+// GetServer returns the server with the given number.
+func (c *Client) GetServer(ctx context.Context, serverNumber int) (*Server, error) {
+	res, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/server/%d", c.url, serverNumber), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	response := serverResponse{}
+	if err = json.Unmarshal(res, &response); err != nil {
+		return nil, err
+	}
+	return &response.Server, nil
+}
+
+// ListServers returns every server of the account. An account without servers is
+// reported as ErrNotFound by Robot, ListServers returns an empty list instead.
+func (c *Client) ListServers(ctx context.Context) ([]Server, error) {
+	res, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/server", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if ErrorCode(err) == "SERVER_NOT_FOUND" {
+		return []Server{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []serverResponse
+	if err = json.Unmarshal(res, &responses); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal servers: %w", err)
+	}
+
+	servers := make([]Server, len(responses))
+	for i, response := range responses {
+		servers[i] = response.Server
+	}
+	return servers, nil
+}
+
+// FindServer returns the first server of the account matching match, or ErrNotFound.
+func (c *Client) FindServer(ctx context.Context, match func(Server) bool) (*Server, error) {
+	servers, err := c.ListServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range servers {
+		if match(servers[i]) {
+			return &servers[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// GetServerByIP returns the server with the given main IPv4 address.
+func (c *Client) GetServerByIP(ctx context.Context, ip string) (*Server, error) {
+	server, err := c.FindServer(ctx, func(server Server) bool {
+		return server.ServerIP == ip
+	})
+	if err != nil {
+		return nil, fmt.Errorf("server with ip %s: %w", ip, err)
+	}
+	return server, nil
+}
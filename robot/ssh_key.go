@@ -0,0 +1,109 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#ssh-keys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type sshKeyResponse struct {
+	Key SSHKey `json:"key"`
+}
+
+// SSHKey is a public key stored in the account, identified by its MD5 fingerprint.
+type SSHKey struct {
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	Type        string `json:"type"`
+	Size        int    `json:"size"`
+	Data        string `json:"data"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// GetSSHKey returns the key with the given fingerprint.
+func (c *Client) GetSSHKey(ctx context.Context, fingerprint string) (*SSHKey, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/key/%s", c.url, fingerprint), nil, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	response := sshKeyResponse{}
+	if err = json.Unmarshal(bytes, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Key, nil
+}
+
+// ListSSHKeys returns every key of the account.
+func (c *Client) ListSSHKeys(ctx context.Context) ([]SSHKey, error) {
+	bytes, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/key", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if ErrorCode(err) == "NOT_FOUND" {
+		return []SSHKey{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []sshKeyResponse
+	if err = json.Unmarshal(bytes, &responses); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal keys: %w", err)
+	}
+
+	keys := make([]SSHKey, len(responses))
+	for i, response := range responses {
+		keys[i] = response.Key
+	}
+	return keys, nil
+}
+
+// CreateSSHKey stores a public key (in OpenSSH or SSH2 format) under the given name.
+func (c *Client) CreateSSHKey(ctx context.Context, name string, data string) (*SSHKey, error) {
+	body := url.Values{}
+	body.Set("name", name)
+	body.Set("data", data)
+
+	bytes, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/key", c.url), body, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	response := sshKeyResponse{}
+	if err = json.Unmarshal(bytes, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Key, nil
+}
+
+// UpdateSSHKey renames a key.
+func (c *Client) UpdateSSHKey(ctx context.Context, fingerprint string, name string) (*SSHKey, error) {
+	body := url.Values{}
+	body.Set("name", name)
+
+	bytes, err := c.makeAPICall(ctx, "PUT", fmt.Sprintf("%s/key/%s", c.url, fingerprint), body, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	response := sshKeyResponse{}
+	if err = json.Unmarshal(bytes, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Key, nil
+}
+
+// DeleteSSHKey removes a key from the account.
+func (c *Client) DeleteSSHKey(ctx context.Context, fingerprint string) error {
+	_, err := c.makeAPICall(ctx, "DELETE", fmt.Sprintf("%s/key/%s", c.url, fingerprint), nil, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package robot
+
+// https://robot.your-server.de/doc/webservice/en.html#vswitch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type VSwitchServer struct {
+	ServerNumber  int    `json:"server_number,omitempty"`
+	ServerIP      string `json:"server_ip,omitempty"`
+	ServerIPv6Net string `json:"server_ipv6_net,omitempty"`
+	Status        string `json:"status,omitempty"`
+}
+
+type VSwitchSubnet struct {
+	IP      string `json:"ip"`
+	Mask    int    `json:"mask"`
+	Gateway string `json:"gateway"`
+}
+
+type VSwitchCloudNetwork struct {
+	ID      int    `json:"id"`
+	IP      string `json:"ip"`
+	Mask    int    `json:"mask"`
+	Gateway string `json:"gateway"`
+}
+
+// VSwitch is a layer 2 network connecting servers of the account.
+type VSwitch struct {
+	ID           int                   `json:"id"`
+	Name         string                `json:"name"`
+	Vlan         int                   `json:"vlan"`
+	Cancelled    bool                  `json:"cancelled"`
+	Server       []VSwitchServer       `json:"server"`
+	Subnet       []VSwitchSubnet       `json:"subnet"`
+	CloudNetwork []VSwitchCloudNetwork `json:"cloud_network"`
+}
+
+// GetVSwitch returns the vSwitch with the given id, including its servers.
+func (c *Client) GetVSwitch(ctx context.Context, id int) (*VSwitch, error) {
+	res, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/vswitch/%d", c.url, id), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	vSwitch := VSwitch{}
+	if err = json.Unmarshal(res, &vSwitch); err != nil {
+		return nil, err
+	}
+	return &vSwitch, nil
+}
+
+// ListVSwitches returns every vSwitch of the account. Robot leaves out servers,
+// subnets and cloud networks, use GetVSwitch for those.
+func (c *Client) ListVSwitches(ctx context.Context) ([]VSwitch, error) {
+	res, err := c.makeAPICall(ctx, "GET", fmt.Sprintf("%s/vswitch", c.url), nil, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	var vSwitches []VSwitch
+	if err = json.Unmarshal(res, &vSwitches); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal vswitches: %w", err)
+	}
+	return vSwitches, nil
+}
+
+// CreateVSwitch creates a vSwitch on the given VLAN (4000-4091).
+func (c *Client) CreateVSwitch(ctx context.Context, name string, vlan int) (*VSwitch, error) {
+	data := url.Values{}
+	data.Set("vlan", strconv.Itoa(vlan))
+	data.Set("name", name)
+	res, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/vswitch", c.url), data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return nil, err
+	}
+
+	vSwitch := VSwitch{}
+	if err = json.Unmarshal(res, &vSwitch); err != nil {
+		return nil, err
+	}
+	return &vSwitch, nil
+}
+
+// UpdateVSwitch changes name and VLAN of a vSwitch.
+func (c *Client) UpdateVSwitch(ctx context.Context, id int, name string, vlan int) error {
+	data := url.Values{}
+	data.Set("vlan", strconv.Itoa(vlan))
+	data.Set("name", name)
+	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/vswitch/%d", c.url, id), data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddVSwitchServers connects servers to a vSwitch.
+func (c *Client) AddVSwitchServers(ctx context.Context, id int, servers []VSwitchServer) error {
+	data := url.Values{}
+	for _, server := range servers {
+		data.Add("server", strconv.Itoa(server.ServerNumber))
+	}
+	_, err := c.makeAPICall(ctx, "POST", fmt.Sprintf("%s/vswitch/%d/server", c.url, id), data, []int{http.StatusOK, http.StatusCreated, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RemoveVSwitchServers disconnects servers from a vSwitch.
+func (c *Client) RemoveVSwitchServers(ctx context.Context, id int, servers []VSwitchServer) error {
+	data := url.Values{}
+	for _, server := range servers {
+		data.Add("server", strconv.Itoa(server.ServerNumber))
+	}
+	_, err := c.makeAPICall(ctx, "DELETE", fmt.Sprintf("%s/vswitch/%d/server", c.url, id), data, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteVSwitch cancels a vSwitch immediately.
+func (c *Client) DeleteVSwitch(ctx context.Context, id int) error {
+	data := url.Values{}
+	data.Set("cancellation_date", "now")
+	_, err := c.makeAPICall(ctx, "DELETE", fmt.Sprintf("%s/vswitch/%d", c.url, id), data, []int{http.StatusOK, http.StatusAccepted})
+	if err != nil {
+		return err
+	}
+	return nil
+}